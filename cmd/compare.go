@@ -0,0 +1,164 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	internalEstimate "github.com/carboniferio/carbonifer/internal/estimate"
+	"github.com/carboniferio/carbonifer/internal/estimate/estimation"
+	"github.com/carboniferio/carbonifer/internal/output"
+	internalProviders "github.com/carboniferio/carbonifer/internal/providers"
+	internalResources "github.com/carboniferio/carbonifer/internal/resources"
+	"github.com/carboniferio/carbonifer/pkg/estimate"
+	"github.com/carboniferio/carbonifer/pkg/providers"
+	"github.com/carboniferio/carbonifer/pkg/resources"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// compareCmd represents the compare command
+var compareCmd = &cobra.Command{
+	Use:   "compare",
+	Short: "Compare CO2 of instance types or regions side by side",
+	Long: `Compare CO2 of one instance of several machine types in a zone, or of one machine type in several zones.
+
+Instances are sorted from the lowest emissions per hour. Instances that cannot be estimated, such as unknown
+machine types, are listed last with the reason.
+Example usages:
+	carbonifer compare --provider gcp --zone europe-west1-b --type n2-standard-8,e2-standard-8,c3-standard-8
+	carbonifer compare --provider gcp --zone europe-west1-b --type n2-standard-8 --custom 8:16
+	carbonifer compare --provider aws --type m6i.large --zone eu-west-1a,eu-west-3a,us-east-1a`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		log.Debug("Running command 'compare'")
+
+		// Instances are compared per hour, then per month and per year
+		estimator, err := estimate.NewEstimator(estimate.Options{
+			Config: viper.AllSettings(),
+			Units:  estimate.Units{Time: "h"},
+		})
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		report, err := compareResources(cmd, estimator.Config())
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		switch viper.Get("out.format") {
+		case "json":
+			writeOutput(cmd, output.GenerateComparisonJSON(report))
+		case "sci":
+			log.Fatal("Output format 'sci' is not supported by compare, use 'text' or 'json'")
+		default:
+			writeOutput(cmd, output.GenerateComparisonText(report))
+		}
+	},
+}
+
+// compareResources returns the comparison of the instances of the flags of the compare command. Instances whose machine
+// type cannot be read are listed last, after the ones that cannot be estimated, with the reason.
+func compareResources(cmd *cobra.Command, cfg *viper.Viper) (estimation.ComparisonReport, error) {
+	candidates, failedCandidates, err := getComparedResources(cmd, cfg.GetString("data.path"))
+	if err != nil {
+		return estimation.ComparisonReport{}, err
+	}
+	report, err := internalEstimate.CompareResources(cfg, candidates)
+	if err != nil {
+		return estimation.ComparisonReport{}, err
+	}
+	report.Candidates = append(report.Candidates, failedCandidates...)
+	return report, nil
+}
+
+// getComparedResources returns one instance of each machine type and custom specs in each zone of the flags of the compare command,
+// read from the data files of dataPath, and the candidates whose machine type cannot be read, with the reason
+func getComparedResources(cmd *cobra.Command, dataPath string) ([]internalResources.ComputeResource, []estimation.ComparisonCandidate, error) {
+	providerName, _ := cmd.Flags().GetString("provider")
+	instanceTypes, _ := cmd.Flags().GetStringSlice("type")
+	customSpecs, _ := cmd.Flags().GetStringSlice("custom")
+	zones, _ := cmd.Flags().GetStringSlice("zone")
+
+	provider, err := providers.ParseProvider(providerName)
+	if err != nil {
+		return nil, nil, errors.Errorf("Unknown provider '%v', must be 'gcp' or 'aws'", providerName)
+	}
+	instancesCount := len(instanceTypes) + len(customSpecs)
+	if instancesCount == 0 {
+		return nil, nil, errors.New("Nothing to compare, set machine types with --type or custom specs with --custom")
+	}
+	if len(zones) == 0 {
+		return nil, nil, errors.New("No zone to compare in, set it with --zone")
+	}
+	if instancesCount > 1 && len(zones) > 1 {
+		return nil, nil, errors.New("Compare either several machine types in one zone, or one machine type in several zones")
+	}
+
+	compared := []internalResources.ComputeResource{}
+	failed := []estimation.ComparisonCandidate{}
+	for _, zone := range zones {
+		for _, instanceType := range instanceTypes {
+			genericResource, err := resources.GetResourceWithDataPath(dataPath, instanceType, zone, provider)
+			if err != nil {
+				log.Warnf("Cannot compare %v in %v: %v", instanceType, zone, err)
+				failed = append(failed, estimation.ComparisonCandidate{
+					Name:     instanceType,
+					Provider: internalProviders.Provider(provider),
+					Region:   resources.RegionOfZone(zone, provider),
+					Error:    err.Error(),
+				})
+				continue
+			}
+			compared = append(compared, toComputeResource(genericResource))
+		}
+		for _, customSpec := range customSpecs {
+			vCPUs, memoryMb, err := parseCustomSpec(customSpec)
+			if err != nil {
+				return nil, nil, err
+			}
+			compared = append(compared, toComputeResource(resources.GenericResource{
+				Name:     fmt.Sprintf("custom-%v-%v", vCPUs, memoryMb),
+				Region:   resources.RegionOfZone(zone, provider),
+				Provider: provider,
+				VCPUs:    vCPUs,
+				MemoryMb: memoryMb,
+			}))
+		}
+	}
+	return compared, failed, nil
+}
+
+// parseCustomSpec parses custom specs such as "8:32", 8 vCPUs and 32 GB of memory, into vCPUs and memory in MB
+func parseCustomSpec(customSpec string) (int32, int32, error) {
+	vCPUsStr, memoryGbStr, ok := strings.Cut(customSpec, ":")
+	if !ok {
+		return 0, 0, errors.Errorf("Invalid custom specs '%v', expected <vCPUs>:<memory in GB>", customSpec)
+	}
+	vCPUs, err := strconv.ParseInt(vCPUsStr, 10, 32)
+	if err != nil || vCPUs < 1 {
+		return 0, 0, errors.Errorf("Invalid number of vCPUs in custom specs '%v'", customSpec)
+	}
+	memoryGb, err := strconv.ParseFloat(memoryGbStr, 64)
+	if err != nil || memoryGb <= 0 {
+		return 0, 0, errors.Errorf("Invalid memory in custom specs '%v'", customSpec)
+	}
+	return int32(vCPUs), int32(memoryGb * 1024), nil
+}
+
+func init() {
+	RootCmd.AddCommand(compareCmd)
+
+	compareCmd.Flags().String("provider", "", "cloud provider ('gcp' or 'aws')")
+	compareCmd.Flags().StringSlice("type", []string{}, "machine or instance types, such as 'n2-standard-8,e2-standard-8'")
+	compareCmd.Flags().StringSlice("custom", []string{}, "custom specs, as <vCPUs>:<memory in GB>, such as '8:32'")
+	compareCmd.Flags().StringSlice("zone", []string{}, "zones or regions, such as 'europe-west1-b' or 'eu-west-1a,eu-west-3a'")
+	for _, flag := range []string{"provider", "zone"} {
+		if err := compareCmd.MarkFlagRequired(flag); err != nil {
+			log.Panic(err)
+		}
+	}
+}
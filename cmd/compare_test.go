@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+
+	_ "github.com/carboniferio/carbonifer/internal/testutils"
+	"github.com/spf13/viper"
+)
+
+func newCompareTestCmd(t *testing.T, args ...string) *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Flags().String("provider", "", "")
+	cmd.Flags().StringSlice("type", []string{}, "")
+	cmd.Flags().StringSlice("custom", []string{}, "")
+	cmd.Flags().StringSlice("zone", []string{}, "")
+	assert.NoError(t, cmd.Flags().Parse(args))
+	return cmd
+}
+
+func TestGetComparedResources_Types(t *testing.T) {
+	cmd := newCompareTestCmd(t, "--provider", "gcp", "--zone", "europe-west1-b", "--type", "n1-standard-2,e2-standard-2", "--custom", "2:4")
+
+	compared, failed, err := getComparedResources(cmd, viper.GetString("data.path"))
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Len(t, compared, 3)
+	assert.Empty(t, failed)
+	assert.Equal(t, "gcp_instance.n1-standard-2", compared[0].GetAddress())
+	assert.Equal(t, "gcp_instance.e2-standard-2", compared[1].GetAddress())
+	assert.Equal(t, "gcp_instance.custom-2-4096", compared[2].GetAddress())
+	assert.Equal(t, int32(2), compared[2].Specs.VCPUs)
+	assert.Equal(t, int32(4096), compared[2].Specs.MemoryMb)
+	for _, resource := range compared {
+		assert.Equal(t, "europe-west1", resource.Identification.Region)
+		assert.Equal(t, int64(1), resource.Identification.Count)
+	}
+}
+
+func TestGetComparedResources_Zones(t *testing.T) {
+	cmd := newCompareTestCmd(t, "--provider", "aws", "--type", "m6i.large", "--zone", "eu-west-1a,eu-west-3")
+
+	compared, failed, err := getComparedResources(cmd, viper.GetString("data.path"))
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Len(t, compared, 2)
+	assert.Empty(t, failed)
+	assert.Equal(t, "eu-west-1", compared[0].Identification.Region)
+	assert.Equal(t, "eu-west-3", compared[1].Identification.Region)
+}
+
+func TestGetComparedResources_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"unknown provider", []string{"--provider", "foo", "--type", "n1-standard-2", "--zone", "europe-west1-b"}},
+		{"nothing to compare", []string{"--provider", "gcp", "--zone", "europe-west1-b"}},
+		{"no zone", []string{"--provider", "gcp", "--type", "n1-standard-2"}},
+		{"several types and zones", []string{"--provider", "gcp", "--type", "n1-standard-2,e2-standard-2", "--zone", "europe-west1-b,us-central1-a"}},
+		{"invalid custom", []string{"--provider", "gcp", "--custom", "2", "--zone", "europe-west1-b"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := getComparedResources(newCompareTestCmd(t, tt.args...), viper.GetString("data.path"))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestCompareResources_UnknownType(t *testing.T) {
+	cmd := newCompareTestCmd(t, "--provider", "gcp", "--zone", "europe-west1-b", "--type", "n1-foo-2,n1-standard-2,e2-standard-2")
+
+	report, err := compareResources(cmd, viper.GetViper())
+	if !assert.NoError(t, err) {
+		return
+	}
+	if !assert.Len(t, report.Candidates, 3) {
+		return
+	}
+	for _, candidate := range report.Candidates[:2] {
+		assert.Empty(t, candidate.Error)
+		assert.True(t, candidate.CarbonEmissionsPerHour.IsPositive())
+	}
+	failed := report.Candidates[2]
+	assert.Equal(t, "n1-foo-2", failed.Name)
+	assert.Equal(t, "europe-west1", failed.Region)
+	assert.Contains(t, failed.Error, "n1-foo-2")
+}
+
+func TestParseCustomSpec(t *testing.T) {
+	vCPUs, memoryMb, err := parseCustomSpec("8:32")
+	assert.NoError(t, err)
+	assert.Equal(t, int32(8), vCPUs)
+	assert.Equal(t, int32(32768), memoryMb)
+
+	vCPUs, memoryMb, err = parseCustomSpec("1:0.5")
+	assert.NoError(t, err)
+	assert.Equal(t, int32(1), vCPUs)
+	assert.Equal(t, int32(512), memoryMb)
+
+	for _, invalid := range []string{"8", "0:32", "eight:32", "8:0", "8:lots"} {
+		_, _, err = parseCustomSpec(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
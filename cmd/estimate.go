@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	internalProviders "github.com/carboniferio/carbonifer/internal/providers"
+	internalResources "github.com/carboniferio/carbonifer/internal/resources"
+	"github.com/carboniferio/carbonifer/pkg/estimate"
+	"github.com/carboniferio/carbonifer/pkg/providers"
+	"github.com/carboniferio/carbonifer/pkg/resources"
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// estimateCmd represents the estimate command
+var estimateCmd = &cobra.Command{
+	Use:   "estimate",
+	Short: "Estimate CO2 of an instance type, without terraform",
+	Long: `Estimate CO2 of instances of a machine type in a zone, without any terraform project.
+
+The report is the same as the one of the 'plan' command.
+Example usages:
+	carbonifer estimate --provider gcp --type n2-standard-8 --zone europe-west1-b
+	carbonifer estimate --provider aws --type m6i.large --zone eu-west-1a --count 3
+	carbonifer estimate --provider gcp --type n1-standard-4 --zone us-central1-a --gpu nvidia-tesla-t4:2 --ssd 100`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		log.Debug("Running command 'estimate'")
+
+		estimator, err := estimate.NewEstimator(estimate.Options{Config: viper.AllSettings()})
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		cfg := estimator.Config()
+		resource, err := getInstanceResource(cmd, cfg.GetString("data.path"))
+		if err != nil {
+			log.Fatal(err)
+		}
+		estimations, sciReport, err := estimateReport(cfg, map[string]internalResources.Resource{
+			resource.GetAddress(): resource,
+		})
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		writeReport(cmd, estimations, sciReport)
+	},
+}
+
+// getInstanceResource returns the resource described by the flags of the estimate command, read from the data files of dataPath
+func getInstanceResource(cmd *cobra.Command, dataPath string) (internalResources.ComputeResource, error) {
+	providerName, _ := cmd.Flags().GetString("provider")
+	instanceType, _ := cmd.Flags().GetString("type")
+	zone, _ := cmd.Flags().GetString("zone")
+	count, _ := cmd.Flags().GetInt64("count")
+	gpus, _ := cmd.Flags().GetStringSlice("gpu")
+	ssd, _ := cmd.Flags().GetFloat64("ssd")
+
+	provider, err := providers.ParseProvider(providerName)
+	if err != nil {
+		return internalResources.ComputeResource{}, errors.Errorf("Unknown provider '%v', must be 'gcp' or 'aws'", providerName)
+	}
+	if count < 1 {
+		return internalResources.ComputeResource{}, errors.Errorf("Invalid count %v, must be at least 1", count)
+	}
+	if ssd < 0 {
+		return internalResources.ComputeResource{}, errors.Errorf("Invalid SSD size %v GB, must be positive", ssd)
+	}
+
+	genericResource, err := resources.GetResourceWithDataPath(dataPath, instanceType, zone, provider)
+	if err != nil {
+		return internalResources.ComputeResource{}, err
+	}
+	resource := toComputeResource(genericResource)
+	resource.Identification.Count = count
+	for _, gpu := range gpus {
+		gpuType, gpuCount, err := parseGPU(dataPath, gpu)
+		if err != nil {
+			return internalResources.ComputeResource{}, err
+		}
+		for i := 0; i < gpuCount; i++ {
+			resource.Specs.GpuTypes = append(resource.Specs.GpuTypes, gpuType)
+		}
+	}
+	resource.Specs.SsdStorage = resource.Specs.SsdStorage.Add(decimal.NewFromFloat(ssd))
+	return resource, nil
+}
+
+// toComputeResource returns the compute resource of one instance of a generic resource, addressed as <provider>_instance.<name>
+func toComputeResource(genericResource resources.GenericResource) internalResources.ComputeResource {
+	identification := genericResource.GetIdentification()
+	identification.ResourceType = fmt.Sprintf("%v_instance", strings.ToLower(genericResource.Provider.String()))
+	identification.Address = fmt.Sprintf("%v.%v", identification.ResourceType, genericResource.Name)
+	return internalResources.ComputeResource{
+		Identification: identification,
+		Specs: &internalResources.ComputeResourceSpecs{
+			GpuTypes:   append([]string{}, genericResource.GPUTypes...),
+			CPUTypes:   genericResource.CPUTypes,
+			HddStorage: genericResource.Storage.HddStorage,
+			SsdStorage: genericResource.Storage.SsdStorage,
+			MemoryMb:   genericResource.MemoryMb,
+			VCPUs:      genericResource.VCPUs,
+		},
+	}
+}
+
+// parseGPU parses a GPU flag such as "nvidia-tesla-t4:2" into its type and count, 1 if not set
+func parseGPU(dataPath string, gpu string) (string, int, error) {
+	gpuType, countStr, hasCount := strings.Cut(gpu, ":")
+	count := 1
+	if hasCount {
+		var err error
+		count, err = strconv.Atoi(countStr)
+		if err != nil || count < 1 {
+			return "", 0, errors.Errorf("Invalid GPU count in '%v', expected <type>:<count>", gpu)
+		}
+	}
+	gpuWatt, err := internalProviders.GetGPUWatt(dataPath, gpuType)
+	if err != nil {
+		return "", 0, err
+	}
+	if gpuWatt.Name == "" {
+		return "", 0, errors.Errorf("Unknown GPU type '%v'", gpuType)
+	}
+	return gpuType, count, nil
+}
+
+func init() {
+	RootCmd.AddCommand(estimateCmd)
+
+	estimateCmd.Flags().String("provider", "", "cloud provider ('gcp' or 'aws')")
+	estimateCmd.Flags().String("type", "", "machine or instance type, such as 'n2-standard-8' or 'm6i.large'")
+	estimateCmd.Flags().String("zone", "", "zone or region, such as 'europe-west1-b' or 'eu-west-1a'")
+	estimateCmd.Flags().Int64("count", 1, "number of instances")
+	estimateCmd.Flags().StringSlice("gpu", []string{}, "GPUs added to each instance, as <type>[:<count>], such as 'nvidia-tesla-t4:2'")
+	estimateCmd.Flags().Float64("ssd", 0, "SSD storage added to each instance, in GB")
+	for _, flag := range []string{"provider", "type", "zone"} {
+		if err := estimateCmd.MarkFlagRequired(flag); err != nil {
+			log.Panic(err)
+		}
+	}
+}
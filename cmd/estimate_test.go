@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/carboniferio/carbonifer/internal/providers"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+
+	_ "github.com/carboniferio/carbonifer/internal/testutils"
+	"github.com/spf13/viper"
+)
+
+func newEstimateTestCmd(t *testing.T, args ...string) *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Flags().String("provider", "", "")
+	cmd.Flags().String("type", "", "")
+	cmd.Flags().String("zone", "", "")
+	cmd.Flags().Int64("count", 1, "")
+	cmd.Flags().StringSlice("gpu", []string{}, "")
+	cmd.Flags().Float64("ssd", 0, "")
+	assert.NoError(t, cmd.Flags().Parse(args))
+	return cmd
+}
+
+func TestGetInstanceResource(t *testing.T) {
+	cmd := newEstimateTestCmd(t, "--provider", "gcp", "--type", "n1-standard-2", "--zone", "europe-west1-b", "--count", "2", "--gpu", "nvidia-tesla-t4:2", "--ssd", "100")
+
+	resource, err := getInstanceResource(cmd, viper.GetString("data.path"))
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "gcp_instance.n1-standard-2", resource.GetAddress())
+	assert.Equal(t, "gcp_instance", resource.Identification.ResourceType)
+	assert.Equal(t, providers.GCP, resource.Identification.Provider)
+	assert.Equal(t, "europe-west1", resource.Identification.Region)
+	assert.Equal(t, int64(2), resource.Identification.Count)
+	assert.Equal(t, int32(2), resource.Specs.VCPUs)
+	assert.Equal(t, int32(7680), resource.Specs.MemoryMb)
+	assert.Equal(t, []string{"nvidia-tesla-t4", "nvidia-tesla-t4"}, resource.Specs.GpuTypes)
+	assert.Equal(t, "100", resource.Specs.SsdStorage.String())
+}
+
+func TestGetInstanceResource_AWS(t *testing.T) {
+	cmd := newEstimateTestCmd(t, "--provider", "aws", "--type", "m6i.large", "--zone", "eu-west-1a")
+
+	resource, err := getInstanceResource(cmd, viper.GetString("data.path"))
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "aws_instance.m6i.large", resource.GetAddress())
+	assert.Equal(t, "eu-west-1", resource.Identification.Region)
+	assert.Equal(t, int64(1), resource.Identification.Count)
+	assert.Equal(t, int32(2), resource.Specs.VCPUs)
+}
+
+func TestGetInstanceResource_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"unknown provider", []string{"--provider", "foo", "--type", "n1-standard-2", "--zone", "europe-west1-b"}},
+		{"unknown type", []string{"--provider", "gcp", "--type", "n2-foo-8", "--zone", "europe-west1-b"}},
+		{"zero count", []string{"--provider", "gcp", "--type", "n1-standard-2", "--zone", "europe-west1-b", "--count", "0"}},
+		{"negative ssd", []string{"--provider", "gcp", "--type", "n1-standard-2", "--zone", "europe-west1-b", "--ssd", "-1"}},
+		{"unknown gpu", []string{"--provider", "gcp", "--type", "n1-standard-2", "--zone", "europe-west1-b", "--gpu", "foo"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := getInstanceResource(newEstimateTestCmd(t, tt.args...), viper.GetString("data.path"))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestParseGPU(t *testing.T) {
+	gpuType, count, err := parseGPU(viper.GetString("data.path"), "nvidia-tesla-t4")
+	assert.NoError(t, err)
+	assert.Equal(t, "nvidia-tesla-t4", gpuType)
+	assert.Equal(t, 1, count)
+
+	gpuType, count, err = parseGPU(viper.GetString("data.path"), "nvidia-tesla-v100:4")
+	assert.NoError(t, err)
+	assert.Equal(t, "nvidia-tesla-v100", gpuType)
+	assert.Equal(t, 4, count)
+
+	_, _, err = parseGPU(viper.GetString("data.path"), "nvidia-tesla-t4:zero")
+	assert.Error(t, err)
+	_, _, err = parseGPU(viper.GetString("data.path"), "nvidia-tesla-t4:0")
+	assert.Error(t, err)
+}
@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	internalEstimate "github.com/carboniferio/carbonifer/internal/estimate"
+	"github.com/carboniferio/carbonifer/internal/output"
+	"github.com/carboniferio/carbonifer/internal/plan"
+	internalResources "github.com/carboniferio/carbonifer/internal/resources"
+	"github.com/carboniferio/carbonifer/internal/terraform"
+	"github.com/carboniferio/carbonifer/pkg/estimate"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// explainCmd represents the explain command
+var explainCmd = &cobra.Command{
+	Use:   "explain <address> [target]",
+	Short: "Explain how a resource of the plan is estimated",
+	Long: `Explain how a resource of a terraform plan is estimated: the mapping that matched it,
+the paths that resolved its properties, and each step of the energy and carbon formula
+with the coefficients used and their data files.
+
+The target is the same as the one of the 'plan' command, the current directory by default.
+Example usages:
+	carbonifer explain google_compute_instance.first
+	carbonifer explain 'module.app.aws_instance.web[0]' /path/to/terraform/plan.json`,
+	Args: cobra.RangeArgs(1, 2),
+	Run: func(cmd *cobra.Command, args []string) {
+		log.Debug("Running command 'explain'")
+
+		workdir, err := os.Getwd()
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		address := args[0]
+		input := workdir
+		if len(args) > 1 {
+			input = args[1]
+			if !filepath.IsAbs(input) {
+				input = filepath.Join(workdir, input)
+			}
+		}
+
+		estimator, err := estimate.NewEstimator(estimate.Options{Config: viper.AllSettings()})
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		tfPlan, err := terraform.CarboniferPlan(input)
+		if err != nil {
+			log.Fatal(err)
+		}
+		explanation, err := explainResource(estimator.Config(), tfPlan, address)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		switch viper.Get("out.format") {
+		case "json":
+			writeOutput(cmd, output.GenerateExplanationJSON(explanation))
+		case "sci":
+			log.Fatal("Output format 'sci' is not supported by explain, use 'text' or 'json'")
+		default:
+			writeOutput(cmd, output.GenerateExplanationText(explanation))
+		}
+	},
+}
+
+// explainResource explains how the resource of the address is read from the terraform plan, then estimated
+func explainResource(cfg *viper.Viper, tfPlan *map[string]interface{}, address string) (output.Explanation, error) {
+	planExplanation, err := plan.ExplainResource(cfg, tfPlan, address)
+	if err != nil {
+		return output.Explanation{}, errors.Wrap(err, "Failed to get resource from terraform plan")
+	}
+	report := internalEstimate.EstimateResources(cfg, map[string]internalResources.Resource{address: planExplanation.Resource})
+	explanation := output.Explanation{
+		Plan: planExplanation,
+		Info: report.Info,
+	}
+	if len(report.Resources) > 0 {
+		explanation.Estimation = &report.Resources[0]
+		if explanation.Estimation.Error != "" {
+			return explanation, nil
+		}
+	}
+	explanation.Steps, err = internalEstimate.ExplainResource(cfg, planExplanation.Resource)
+	if err != nil {
+		return output.Explanation{}, err
+	}
+	return explanation, nil
+}
+
+func init() {
+	RootCmd.AddCommand(explainCmd)
+}
@@ -0,0 +1,265 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/carboniferio/carbonifer/internal/estimate/coefficients"
+	"github.com/carboniferio/carbonifer/internal/output"
+	"github.com/carboniferio/carbonifer/internal/plan"
+	"github.com/carboniferio/carbonifer/internal/providers"
+	"github.com/carboniferio/carbonifer/internal/providers/aws"
+	"github.com/carboniferio/carbonifer/internal/providers/gcp"
+	"github.com/carboniferio/carbonifer/pkg/estimate"
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// listCmd represents the list command
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the supported resources, regions, instance types and GPUs",
+	Long: `List what carbonifer can estimate, from the mappings and data files in use.
+
+Example usages:
+	carbonifer list resources
+	carbonifer list regions --provider aws
+	carbonifer list instance-types --provider gcp -f json
+	carbonifer list gpus`,
+}
+
+// listedResource is a terraform resource type of the mappings
+type listedResource struct {
+	Provider     providers.Provider
+	ResourceType string // name or regular expression if ignored
+	Status       string // "estimated" or "ignored"
+}
+
+// listedRegion is a region of the data files
+type listedRegion struct {
+	Provider            providers.Provider
+	Region              string
+	Location            string
+	GridCarbonIntensity decimal.Decimal // gCO2eq/kWh
+}
+
+// listedInstanceType is a machine or instance type of the data files
+type listedInstanceType struct {
+	Provider     providers.Provider
+	Name         string
+	VCPUs        int32
+	MemoryMb     int32
+	GPUs         []string `json:",omitempty"`
+	CPUPlatforms []string `json:",omitempty"`
+}
+
+// listedGPU is a GPU of the data files
+type listedGPU struct {
+	Name     string
+	MinWatts decimal.Decimal
+	MaxWatts decimal.Decimal
+}
+
+var listResourcesCmd = &cobra.Command{
+	Use:   "resources",
+	Short: "List the terraform resource types estimated or ignored",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		listed := []listedResource{}
+		runList(cmd, func(cfg *viper.Viper, providerList []providers.Provider) error {
+			supported, ignored, err := plan.ResourceTypes(cfg)
+			if err != nil {
+				return err
+			}
+			for _, provider := range providerList {
+				for _, resourceType := range supported[provider] {
+					listed = append(listed, listedResource{Provider: provider, ResourceType: resourceType, Status: "estimated"})
+				}
+				for _, resourceType := range ignored[provider] {
+					listed = append(listed, listedResource{Provider: provider, ResourceType: resourceType, Status: "ignored"})
+				}
+			}
+			return nil
+		})
+
+		rows := [][]string{}
+		for _, resource := range listed {
+			rows = append(rows, []string{resource.Provider.String(), resource.ResourceType, resource.Status})
+		}
+		writeList(cmd, "Terraform resource types", []string{"provider", "resource type", "status"}, rows, listed)
+	},
+}
+
+var listRegionsCmd = &cobra.Command{
+	Use:   "regions",
+	Short: "List the regions and their grid carbon intensity",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		listed := []listedRegion{}
+		runList(cmd, func(cfg *viper.Viper, providerList []providers.Provider) error {
+			for _, provider := range providerList {
+				regions, err := coefficients.RegionEmissions(cfg.GetString("data.path"), provider)
+				if err != nil {
+					return err
+				}
+				for _, region := range regions {
+					listed = append(listed, listedRegion{
+						Provider:            provider,
+						Region:              region.Region,
+						Location:            region.Location,
+						GridCarbonIntensity: region.GridCarbonIntensity,
+					})
+				}
+			}
+			return nil
+		})
+
+		rows := [][]string{}
+		for _, region := range listed {
+			rows = append(rows, []string{region.Provider.String(), region.Region, region.Location, region.GridCarbonIntensity.String()})
+		}
+		writeList(cmd, "Regions", []string{"provider", "region", "location", "grid carbon intensity (gCO2eq/kWh)"}, rows, listed)
+	},
+}
+
+var listInstanceTypesCmd = &cobra.Command{
+	Use:   "instance-types",
+	Short: "List the machine and instance types and their specs",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		listed := []listedInstanceType{}
+		runList(cmd, func(cfg *viper.Viper, providerList []providers.Provider) error {
+			for _, provider := range providerList {
+				switch provider {
+				case providers.GCP:
+					machineTypes, err := gcp.GetGCPMachineTypes(cfg.GetString("data.path"))
+					if err != nil {
+						return err
+					}
+					for _, machineType := range machineTypes {
+						listed = append(listed, listedInstanceType{
+							Provider:     provider,
+							Name:         machineType.Name,
+							VCPUs:        machineType.Vcpus,
+							MemoryMb:     machineType.MemoryMb,
+							GPUs:         machineType.GPUTypes,
+							CPUPlatforms: machineType.CPUTypes,
+						})
+					}
+				case providers.AWS:
+					instanceTypes, err := aws.GetAWSInstanceTypes(cfg.GetString("data.path"))
+					if err != nil {
+						return err
+					}
+					for _, instanceType := range instanceTypes {
+						listed = append(listed, listedInstanceType{
+							Provider: provider,
+							Name:     instanceType.InstanceType,
+							VCPUs:    instanceType.VCPU,
+							MemoryMb: instanceType.MemoryMb,
+							GPUs:     instanceType.GPUTypes(),
+						})
+					}
+				}
+			}
+			return nil
+		})
+
+		rows := [][]string{}
+		for _, instanceType := range listed {
+			rows = append(rows, []string{
+				instanceType.Provider.String(),
+				instanceType.Name,
+				fmt.Sprintf("%v", instanceType.VCPUs),
+				output.FormatMemory(instanceType.MemoryMb),
+				formatGPUs(instanceType.GPUs),
+				strings.Join(instanceType.CPUPlatforms, ", "),
+			})
+		}
+		writeList(cmd, "Instance types", []string{"provider", "name", "vCPUs", "memory", "GPUs", "CPU platforms"}, rows, listed)
+	},
+}
+
+var listGPUsCmd = &cobra.Command{
+	Use:   "gpus",
+	Short: "List the GPUs and their power, for all providers",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		listed := []listedGPU{}
+		runList(cmd, func(cfg *viper.Viper, providerList []providers.Provider) error {
+			gpuWatts, err := providers.GetGPUWatts(cfg.GetString("data.path"))
+			if err != nil {
+				return err
+			}
+			for _, gpuWatt := range gpuWatts {
+				listed = append(listed, listedGPU(gpuWatt))
+			}
+			return nil
+		})
+
+		rows := [][]string{}
+		for _, gpu := range listed {
+			rows = append(rows, []string{gpu.Name, gpu.MinWatts.String(), gpu.MaxWatts.String()})
+		}
+		writeList(cmd, "GPUs", []string{"name", "min watts", "max watts"}, rows, listed)
+	},
+}
+
+// runList calls fn with the providers of the --provider flag, or else all the supported ones, with the configuration of carbonifer
+func runList(cmd *cobra.Command, fn func(cfg *viper.Viper, providerList []providers.Provider) error) {
+	estimator, err := estimate.NewEstimator(estimate.Options{Config: viper.AllSettings()})
+	if err != nil {
+		log.Fatal(err)
+	}
+	providerList := []providers.Provider{providers.GCP, providers.AWS}
+	if providerName, _ := cmd.Flags().GetString("provider"); providerName != "" {
+		provider, err := providers.ParseProvider(providerName)
+		if err != nil || (provider != providers.GCP && provider != providers.AWS) {
+			log.Fatal(errors.Errorf("Unknown provider '%v', must be 'gcp' or 'aws'", providerName))
+		}
+		providerList = []providers.Provider{provider}
+	}
+	err = fn(estimator.Config(), providerList)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// writeList prints out a list as a table, or as JSON if it is the output format
+func writeList(cmd *cobra.Command, title string, header []string, rows [][]string, list interface{}) {
+	switch viper.Get("out.format") {
+	case "json":
+		writeOutput(cmd, output.GenerateListJSON(list))
+	case "sci":
+		log.Fatal("Output format 'sci' is not supported by list, use 'text' or 'json'")
+	default:
+		writeOutput(cmd, output.GenerateListText(title, header, rows))
+	}
+}
+
+// formatGPUs formats a list of GPUs with one item per GPU, such as "2 x nvidia-tesla-t4"
+func formatGPUs(gpus []string) string {
+	counts := map[string]int{}
+	names := []string{}
+	for _, gpu := range gpus {
+		if counts[gpu] == 0 {
+			names = append(names, gpu)
+		}
+		counts[gpu]++
+	}
+	formatted := []string{}
+	for _, name := range names {
+		formatted = append(formatted, fmt.Sprintf("%v x %v", counts[name], name))
+	}
+	return strings.Join(formatted, ", ")
+}
+
+func init() {
+	RootCmd.AddCommand(listCmd)
+	listCmd.AddCommand(listResourcesCmd, listRegionsCmd, listInstanceTypesCmd, listGPUsCmd)
+
+	listCmd.PersistentFlags().String("provider", "", "cloud provider ('gcp' or 'aws'), default all")
+}
@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/carboniferio/carbonifer/internal/golden"
+	"github.com/carboniferio/carbonifer/internal/output"
+	"github.com/carboniferio/carbonifer/internal/plan"
+	"github.com/carboniferio/carbonifer/pkg/estimate"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// mappingsCmd represents the mappings command
+var mappingsCmd = &cobra.Command{
+	Use:   "mappings",
+	Short: "Tools for the mappings of terraform resources",
+}
+
+var mappingsValidateCmd = &cobra.Command{
+	Use:   "validate [dir]",
+	Short: "Validate mappings of terraform resources",
+	Long: `Validate the YAML mappings of terraform resources, without any terraform plan.
+
+It reports unknown keys, jq paths that do not compile (placeholders and cbf:: functions included),
+references to json files missing from general.json_data, invalid regular expressions and units.
+The directory has a folder per provider, as internal/plan/mappings. Default is the mappings in use,
+set by 'mappings.path' or else the embedded ones. Exits with an error if any issue is found.
+
+Example usages:
+	carbonifer mappings validate
+	carbonifer mappings validate ./my-mappings -f json`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		log.Debug("Running command 'mappings validate'")
+
+		dir := ""
+		if len(args) > 0 {
+			dir = args[0]
+		}
+
+		estimator, err := estimate.NewEstimator(estimate.Options{Config: viper.AllSettings()})
+		if err != nil {
+			log.Fatal(err)
+		}
+		issues, err := plan.ValidateMappings(estimator.Config(), dir)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		switch viper.Get("out.format") {
+		case "json":
+			writeOutput(cmd, output.GenerateListJSON(issues))
+		case "sci":
+			log.Fatal("Output format 'sci' is not supported by mappings validate, use 'text' or 'json'")
+		default:
+			writeOutput(cmd, formatMappingIssues(issues))
+		}
+		if len(issues) > 0 {
+			log.Fatalf("%v issues found in mappings", len(issues))
+		}
+	},
+}
+
+var mappingsTestCmd = &cobra.Command{
+	Use:   "test <dir>",
+	Short: "Test mappings against snapshots of terraform plan fixtures",
+	Long: `Test the mappings of terraform resources against a directory of terraform plan JSON fixtures,
+as output by 'terraform show -json', without running terraform.
+
+Each fixture is read and estimated, then compared with its snapshot next to it, such as plan.golden.yaml
+for plan.json (or plan.golden.json), holding the specs and emissions of every resource by address.
+With --update, snapshots are written instead, created if missing. Exits with an error if any fixture fails.
+
+Example usages:
+	carbonifer mappings test test/terraform/planJson
+	carbonifer mappings test ./fixtures --update`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		log.Debug("Running command 'mappings test'")
+
+		update, _ := cmd.Flags().GetBool("update")
+		estimator, err := estimate.NewEstimator(estimate.Options{Config: viper.AllSettings()})
+		if err != nil {
+			log.Fatal(err)
+		}
+		results, err := golden.Run(estimator.Config(), args[0], update)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		switch viper.Get("out.format") {
+		case "json":
+			writeOutput(cmd, output.GenerateListJSON(results))
+		case "sci":
+			log.Fatal("Output format 'sci' is not supported by mappings test, use 'text' or 'json'")
+		default:
+			writeOutput(cmd, formatGoldenResults(results))
+		}
+		failed := 0
+		for _, result := range results {
+			if result.Status == golden.StatusFailed {
+				failed++
+			}
+		}
+		if failed > 0 {
+			log.Fatalf("%v of %v fixtures failed", failed, len(results))
+		}
+	},
+}
+
+// formatMappingIssues formats the issues one per line, as file:line: location: message
+func formatMappingIssues(issues []plan.MappingIssue) string {
+	if len(issues) == 0 {
+		return "No issue found in mappings"
+	}
+	lines := []string{}
+	for _, issue := range issues {
+		lines = append(lines, issue.String())
+	}
+	lines = append(lines, "", fmt.Sprintf("%v issues found in mappings", len(issues)))
+	return strings.Join(lines, "\n")
+}
+
+// formatGoldenResults formats the status of each fixture, followed by its differences with its snapshot
+func formatGoldenResults(results []golden.Result) string {
+	if len(results) == 0 {
+		return "No terraform plan JSON fixture found"
+	}
+	lines := []string{}
+	for _, result := range results {
+		lines = append(lines, fmt.Sprintf("%v\t%v", strings.ToUpper(result.Status), result.Fixture))
+		if result.Error != "" {
+			lines = append(lines, "\t"+result.Error)
+		}
+		for _, difference := range result.Differences {
+			lines = append(lines, "\t"+difference)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func init() {
+	RootCmd.AddCommand(mappingsCmd)
+	mappingsCmd.AddCommand(mappingsValidateCmd, mappingsTestCmd)
+
+	mappingsTestCmd.Flags().Bool("update", false, "write the snapshots instead of comparing, created if missing")
+}
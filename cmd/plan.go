@@ -1,17 +1,15 @@
 package cmd
 
 import (
-	"bufio"
 	"os"
 	"path/filepath"
 
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 
-	"github.com/carboniferio/carbonifer/internal/estimate"
-	"github.com/carboniferio/carbonifer/internal/output"
 	"github.com/carboniferio/carbonifer/internal/plan"
 	"github.com/carboniferio/carbonifer/internal/terraform"
+	"github.com/carboniferio/carbonifer/pkg/estimate"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -52,49 +50,41 @@ Example usages:
 			}
 		}
 
+		estimator, err := estimate.NewEstimator(estimate.Options{Config: viper.AllSettings()})
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		cfg := estimator.Config()
+
 		// Generate or Read Terraform plan
 		tfPlan, err := terraform.CarboniferPlan(input)
 		if err != nil {
 			log.Fatal(err)
 		}
 
+		// Read and validate assumptions file
+		assumptions, err := plan.LoadAssumptions(cfg)
+		if err != nil {
+			log.Fatal(err)
+		}
+
 		// Read resources from terraform plan
-		resources, err := plan.GetResources(tfPlan)
+		resources, err := plan.GetResourcesWithAssumptions(cfg, tfPlan, assumptions)
 		if err != nil {
-			errW := errors.Wrap(err, "Failed to get resources from terraform plan")
-			log.Panic(errW)
+			log.Fatal(errors.Wrap(err, "Failed to get resources from terraform plan"))
+		}
+		for _, pattern := range assumptions.UnmatchedResources(resources) {
+			log.Warnf("Assumptions '%v' do not match any resource of the plan", pattern)
 		}
 
 		// Estimate CO2 emissions
-		estimations := estimate.EstimateResources(resources)
-
-		// Generate report
-		reportText := ""
-		if viper.Get("out.format") == "json" {
-			reportText = output.GenerateReportJSON(estimations)
-		} else {
-			reportText = output.GenerateReportText(estimations)
+		estimations, sciReport, err := estimateReport(cfg, resources)
+		if err != nil {
+			log.Fatal(err)
 		}
 
-		// Print out report
-		outFile := viper.Get("out.file").(string)
-		if outFile == "" {
-			log.Debug("output : stdout")
-			cmd.SetOut(os.Stdout)
-			cmd.Println(reportText)
-		} else {
-			log.Debug("output :", outFile)
-			f, err := os.Create(outFile)
-			if err != nil {
-				log.Fatal(err)
-			}
-			outWriter := bufio.NewWriter(f)
-			_, err = outWriter.WriteString(reportText)
-			if err != nil {
-				log.Fatal(err)
-			}
-			outWriter.Flush()
-		}
+		writeReport(cmd, estimations, sciReport)
 	},
 }
 
@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"bufio"
+	"os"
+
+	internalEstimate "github.com/carboniferio/carbonifer/internal/estimate"
+	"github.com/carboniferio/carbonifer/internal/estimate/estimation"
+	"github.com/carboniferio/carbonifer/internal/output"
+	"github.com/carboniferio/carbonifer/internal/resources"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// estimateReport estimates the CO2 emissions of resources, and their SCI score if the output format is sci
+func estimateReport(cfg *viper.Viper, resources map[string]resources.Resource) (estimation.EstimationReport, *estimation.SCIReport, error) {
+	estimations := internalEstimate.EstimateResources(cfg, resources)
+	if viper.Get("out.format") != "sci" {
+		return estimations, nil, nil
+	}
+	sciReport, err := internalEstimate.EstimateSCI(cfg, estimations)
+	if err != nil {
+		return estimations, nil, err
+	}
+	return estimations, sciReport, nil
+}
+
+// writeReport prints out the report in the output format, to the output file or else to standard output
+func writeReport(cmd *cobra.Command, estimations estimation.EstimationReport, sciReport *estimation.SCIReport) {
+	// Generate report
+	reportText := ""
+	switch viper.Get("out.format") {
+	case "json":
+		reportText = output.GenerateReportJSON(estimations, viper.GetBool("out.provenance"))
+	case "sci":
+		reportText = output.GenerateReportSCI(estimations, *sciReport)
+	default:
+		reportText = output.GenerateReportText(estimations)
+	}
+	writeOutput(cmd, reportText)
+}
+
+// writeOutput prints out a report to the output file, or else to standard output
+func writeOutput(cmd *cobra.Command, reportText string) {
+	outFile := viper.Get("out.file").(string)
+	if outFile == "" {
+		log.Debug("output : stdout")
+		cmd.SetOut(os.Stdout)
+		cmd.Println(reportText)
+	} else {
+		log.Debug("output :", outFile)
+		f, err := os.Create(outFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+		outWriter := bufio.NewWriter(f)
+		_, err = outWriter.WriteString(reportText)
+		if err != nil {
+			log.Fatal(err)
+		}
+		outWriter.Flush()
+	}
+}
@@ -38,8 +38,12 @@ func init() {
 	// will be global for your application.
 
 	RootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.carbonifer.yaml)")
-	RootCmd.PersistentFlags().StringP("format", "f", "", "format of output ('text' or 'json').\ndefault: 'text'")
+	RootCmd.PersistentFlags().StringP("format", "f", "", "format of output ('text', 'json' or 'sci').\ndefault: 'text'")
 	RootCmd.PersistentFlags().StringP("output", "o", "", "output file")
+	RootCmd.PersistentFlags().Bool("provenance", false, "include in JSON reports where the value of each property of resources comes from")
+	RootCmd.PersistentFlags().String("assumptions", "", "file of assumptions per resource type or address")
+	RootCmd.PersistentFlags().String("functional-unit", "", "functional unit of the SCI score, such as 'request' or 'user'")
+	RootCmd.PersistentFlags().Float64("functional-unit-count", 0, "number of functional units per unit of time, for the SCI score")
 	RootCmd.PersistentFlags().BoolP("debug", "d", false, "print debug logs")
 	RootCmd.PersistentFlags().BoolP("info", "i", false, "print info logs")
 
@@ -69,4 +73,20 @@ func initConfig() {
 		log.Panic(err)
 	}
 
+	if err := viper.BindPFlag("out.provenance", RootCmd.PersistentFlags().Lookup("provenance")); err != nil {
+		log.Panic(err)
+	}
+
+	if err := viper.BindPFlag("assumptions_file", RootCmd.PersistentFlags().Lookup("assumptions")); err != nil {
+		log.Panic(err)
+	}
+
+	if err := viper.BindPFlag("sci.functional_unit", RootCmd.PersistentFlags().Lookup("functional-unit")); err != nil {
+		log.Panic(err)
+	}
+
+	if err := viper.BindPFlag("sci.functional_unit_count", RootCmd.PersistentFlags().Lookup("functional-unit-count")); err != nil {
+		log.Panic(err)
+	}
+
 }
@@ -8,16 +8,13 @@ import (
 
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
-
-	"github.com/spf13/viper"
 )
 
 //go:embed data/*
 var data embed.FS
 
-// ReadDataFile reads a file from the data directory
-func ReadDataFile(filename string) []byte {
-	dataPath := viper.GetString("data.path")
+// ReadDataFile reads a file from the data directory dataPath, or else the embedded one
+func ReadDataFile(dataPath string, filename string) ([]byte, error) {
 	if dataPath != "" {
 		// If the environment variable is set, read from the specified file
 		filePath := filepath.Join(dataPath, filename)
@@ -25,9 +22,9 @@ func ReadDataFile(filename string) []byte {
 			log.Debugf("  reading datafile '%v' from: %v", filename, filePath)
 			data, err := os.ReadFile(filePath)
 			if err != nil {
-				log.Fatal(err)
+				return nil, &MissingDataError{File: filePath, Err: err}
 			}
-			return data
+			return data, nil
 		}
 		return readEmbeddedFile(filename)
 
@@ -35,12 +32,11 @@ func ReadDataFile(filename string) []byte {
 	return readEmbeddedFile(filename)
 }
 
-func readEmbeddedFile(filename string) []byte {
+func readEmbeddedFile(filename string) ([]byte, error) {
 	log.Debugf("  reading datafile '%v' embedded", filename)
 	data, err := fs.ReadFile(data, "data/"+filename)
 	if err != nil {
-		errW := errors.Wrap(err, "cannot read embedded data file")
-		log.Fatal(errW)
+		return nil, &MissingDataError{File: filename, Err: errors.Wrap(err, "cannot read embedded data file")}
 	}
-	return data
+	return data, nil
 }
@@ -0,0 +1,31 @@
+package data
+
+import "fmt"
+
+// MissingDataError is an error that occurs when a data file cannot be read
+type MissingDataError struct {
+	File string
+	Err  error
+}
+
+func (e *MissingDataError) Error() string {
+	return fmt.Sprintf("Cannot read data file %v: %v", e.File, e.Err)
+}
+
+func (e *MissingDataError) Unwrap() error {
+	return e.Err
+}
+
+// MalformedDataError is an error that occurs when a data file cannot be parsed
+type MalformedDataError struct {
+	File string
+	Err  error
+}
+
+func (e *MalformedDataError) Error() string {
+	return fmt.Sprintf("Malformed data file %v: %v", e.File, e.Err)
+}
+
+func (e *MalformedDataError) Unwrap() error {
+	return e.Err
+}
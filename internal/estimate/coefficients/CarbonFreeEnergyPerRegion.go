@@ -0,0 +1,87 @@
+package coefficients
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/carboniferio/carbonifer/internal/data"
+	"github.com/carboniferio/carbonifer/internal/providers"
+	"github.com/shopspring/decimal"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/yunabe/easycsv"
+)
+
+// carbonFreeEnergyPerRegion is the carbon free energy of the regions of the data files read
+var carbonFreeEnergyPerRegion = map[dataFileKey]map[string]CarbonFreeEnergy{}
+
+// CarbonFreeEnergy is the share of the electricity of a region covered by carbon free energy
+type CarbonFreeEnergy struct {
+	Region           string
+	Location         string
+	CarbonFreeEnergy decimal.Decimal // Ratio between 0 and 1
+	Source           string
+}
+
+// RegionCarbonFreeEnergy returns the carbon free energy of a region
+// No data file is embedded: providers do not publish it for all their regions, so it is only known
+// for the regions of the files `aws_cfe_region.csv` or `gcp_cfe_region.csv` of the `data.path` directory
+func RegionCarbonFreeEnergy(dataPath string, provider providers.Provider, region string) (*CarbonFreeEnergy, error) {
+	var dataFile string
+	switch provider {
+	case providers.AWS:
+		dataFile = "aws_cfe_region.csv"
+	case providers.GCP:
+		dataFile = "gcp_cfe_region.csv"
+	default:
+		return nil, &providers.UnsupportedProviderError{Provider: provider.String()}
+	}
+	coefficientsMutex.Lock()
+	defer coefficientsMutex.Unlock()
+	key := dataFileKey{DataPath: dataPath, File: dataFile}
+	if _, ok := carbonFreeEnergyPerRegion[key]; !ok {
+		regions, err := loadCarbonFreeEnergyPerRegion(dataPath, dataFile)
+		var missingDataErr *data.MissingDataError
+		if errors.As(err, &missingDataErr) {
+			regions = map[string]CarbonFreeEnergy{}
+		} else if err != nil {
+			return nil, err
+		}
+		carbonFreeEnergyPerRegion[key] = regions
+	}
+	carbonFreeEnergy, ok := carbonFreeEnergyPerRegion[key][region]
+	if !ok {
+		return nil, &providers.UnknownRegionError{Provider: provider, Region: region}
+	}
+	return &carbonFreeEnergy, nil
+}
+
+type carbonFreeEnergyCSV struct {
+	Region           string  `name:"Region"`
+	Location         string  `name:"Location"`
+	CarbonFreeEnergy float64 `name:"Carbon free energy (%)"`
+	Source           string  `name:"Source"`
+}
+
+func loadCarbonFreeEnergyPerRegion(dataPath string, dataFile string) (map[string]CarbonFreeEnergy, error) {
+	var records []carbonFreeEnergyCSV
+	regionCarbonFreeEnergyFile, err := data.ReadDataFile(dataPath, dataFile)
+	if err != nil {
+		return nil, err
+	}
+	log.Debugf("reading region carbon free energy from: %v", dataFile)
+	if err := easycsv.NewReader(strings.NewReader(string(regionCarbonFreeEnergyFile))).ReadAll(&records); err != nil {
+		return nil, &data.MalformedDataError{File: dataFile, Err: err}
+	}
+
+	data := make(map[string]CarbonFreeEnergy)
+	for _, record := range records {
+		data[record.Region] = CarbonFreeEnergy{
+			Region:           record.Region,
+			Location:         record.Location,
+			CarbonFreeEnergy: decimal.NewFromFloat(record.CarbonFreeEnergy).Div(decimal.NewFromInt(100)),
+			Source:           record.Source,
+		}
+	}
+	return data, nil
+}
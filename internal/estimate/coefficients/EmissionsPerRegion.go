@@ -1,10 +1,9 @@
 package coefficients
 
 import (
+	"sort"
 	"strings"
 
-	"github.com/pkg/errors"
-
 	"github.com/carboniferio/carbonifer/internal/data"
 	"github.com/carboniferio/carbonifer/internal/providers"
 	"github.com/shopspring/decimal"
@@ -13,8 +12,8 @@ import (
 	"github.com/yunabe/easycsv"
 )
 
-// EmissionsPerRegion is a map of regions to their emissions
-var EmissionsPerRegion map[string]Emissions
+// emissionsPerRegion are the emissions of the regions of the data files read
+var emissionsPerRegion = map[dataFileKey]map[string]Emissions{}
 
 // Emissions is the emissions of a region
 type Emissions struct {
@@ -24,7 +23,35 @@ type Emissions struct {
 }
 
 // RegionEmission returns the emissions of a region
-func RegionEmission(provider providers.Provider, region string) (*Emissions, error) {
+func RegionEmission(dataPath string, provider providers.Provider, region string) (*Emissions, error) {
+	regions, err := providerEmissions(dataPath, provider)
+	if err != nil {
+		return nil, err
+	}
+	emissions, ok := regions[region]
+	if !ok {
+		return nil, &providers.UnknownRegionError{Provider: provider, Region: region}
+	}
+	return &emissions, nil
+}
+
+// RegionEmissions returns the emissions of all the regions of a provider, sorted by region
+func RegionEmissions(dataPath string, provider providers.Provider) ([]Emissions, error) {
+	regions, err := providerEmissions(dataPath, provider)
+	if err != nil {
+		return nil, err
+	}
+	emissions := make([]Emissions, 0, len(regions))
+	for _, regionEmissions := range regions {
+		emissions = append(emissions, regionEmissions)
+	}
+	sort.Slice(emissions, func(i, j int) bool {
+		return emissions[i].Region < emissions[j].Region
+	})
+	return emissions, nil
+}
+
+func providerEmissions(dataPath string, provider providers.Provider) (map[string]Emissions, error) {
 	var dataFile string
 	switch provider {
 	case providers.AWS:
@@ -32,19 +59,19 @@ func RegionEmission(provider providers.Provider, region string) (*Emissions, err
 	case providers.GCP:
 		dataFile = "gcp_co2_region.csv"
 	default:
-		return nil, errors.New("Provider not supported")
-	}
-	if EmissionsPerRegion == nil {
-		EmissionsPerRegion = loadEmissionsPerRegion(dataFile)
-	}
-	if region == "" {
-		return nil, errors.New("Region cannot be empty")
+		return nil, &providers.UnsupportedProviderError{Provider: provider.String()}
 	}
-	emissions, ok := EmissionsPerRegion[region]
-	if !ok {
-		return nil, errors.Errorf("Region does not exist: '%v'", region)
+	coefficientsMutex.Lock()
+	defer coefficientsMutex.Unlock()
+	key := dataFileKey{DataPath: dataPath, File: dataFile}
+	if _, ok := emissionsPerRegion[key]; !ok {
+		regions, err := loadEmissionsPerRegion(dataPath, dataFile)
+		if err != nil {
+			return nil, err
+		}
+		emissionsPerRegion[key] = regions
 	}
-	return &emissions, nil
+	return emissionsPerRegion[key], nil
 }
 
 type emissionsCSV struct {
@@ -54,13 +81,16 @@ type emissionsCSV struct {
 }
 
 // Source: Google
-func loadEmissionsPerRegion(dataFile string) map[string]Emissions {
+func loadEmissionsPerRegion(dataPath string, dataFile string) (map[string]Emissions, error) {
 	// Read the CSV records
 	var records []emissionsCSV
-	regionEmissionFile := data.ReadDataFile(dataFile)
+	regionEmissionFile, err := data.ReadDataFile(dataPath, dataFile)
+	if err != nil {
+		return nil, err
+	}
 	log.Debugf("reading GCP region/grid emissions from: %v", dataFile)
 	if err := easycsv.NewReader(strings.NewReader(string(regionEmissionFile))).ReadAll(&records); err != nil {
-		log.Fatal(err)
+		return nil, &data.MalformedDataError{File: dataFile, Err: err}
 	}
 
 	// Create a map to store the data
@@ -75,5 +105,5 @@ func loadEmissionsPerRegion(dataFile string) map[string]Emissions {
 			GridCarbonIntensity: decimal.NewFromFloat(record.GridCarbonIntensity),
 		}
 	}
-	return data
+	return data, nil
 }
@@ -0,0 +1,26 @@
+package coefficients
+
+import (
+	"testing"
+
+	"github.com/carboniferio/carbonifer/internal/providers"
+	_ "github.com/carboniferio/carbonifer/internal/testutils"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegionEmissions(t *testing.T) {
+	regions, err := RegionEmissions(viper.GetString("data.path"), providers.GCP)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, regions)
+	for i := 1; i < len(regions); i++ {
+		assert.Less(t, regions[i-1].Region, regions[i].Region)
+	}
+
+	emissions, err := RegionEmission(viper.GetString("data.path"), providers.GCP, "europe-west9")
+	assert.NoError(t, err)
+	assert.Contains(t, regions, *emissions)
+
+	_, err = RegionEmissions(viper.GetString("data.path"), providers.AZURE)
+	assert.Error(t, err)
+}
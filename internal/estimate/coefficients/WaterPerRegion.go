@@ -0,0 +1,86 @@
+package coefficients
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/carboniferio/carbonifer/internal/data"
+	"github.com/carboniferio/carbonifer/internal/providers"
+	"github.com/shopspring/decimal"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/yunabe/easycsv"
+)
+
+// waterPerRegion is the water usage effectiveness of the regions of the data files read
+var waterPerRegion = map[dataFileKey]map[string]Water{}
+
+// Water is the water usage effectiveness (WUE) of a region
+type Water struct {
+	Region                  string
+	Location                string
+	WaterUsageEffectiveness decimal.Decimal // Litres per kWh of IT equipment energy
+	Source                  string
+}
+
+// RegionWater returns the water usage effectiveness of a region.
+// A provider without data file, such as GCP which does not publish its WUE, has no region known
+func RegionWater(dataPath string, provider providers.Provider, region string) (*Water, error) {
+	var dataFile string
+	switch provider {
+	case providers.AWS:
+		dataFile = "aws_water_region.csv"
+	case providers.GCP:
+		dataFile = "gcp_water_region.csv"
+	default:
+		return nil, &providers.UnsupportedProviderError{Provider: provider.String()}
+	}
+	coefficientsMutex.Lock()
+	defer coefficientsMutex.Unlock()
+	key := dataFileKey{DataPath: dataPath, File: dataFile}
+	if _, ok := waterPerRegion[key]; !ok {
+		regions, err := loadWaterPerRegion(dataPath, dataFile)
+		var missingDataErr *data.MissingDataError
+		if errors.As(err, &missingDataErr) {
+			regions = map[string]Water{}
+		} else if err != nil {
+			return nil, err
+		}
+		waterPerRegion[key] = regions
+	}
+	water, ok := waterPerRegion[key][region]
+	if !ok {
+		return nil, &providers.UnknownRegionError{Provider: provider, Region: region}
+	}
+	return &water, nil
+}
+
+type waterCSV struct {
+	Region                  string  `name:"Region"`
+	Location                string  `name:"Location"`
+	WaterUsageEffectiveness float64 `name:"Water usage effectiveness (L / kWh)"`
+	Source                  string  `name:"Source"`
+}
+
+func loadWaterPerRegion(dataPath string, dataFile string) (map[string]Water, error) {
+	var records []waterCSV
+	regionWaterFile, err := data.ReadDataFile(dataPath, dataFile)
+	if err != nil {
+		return nil, err
+	}
+	log.Debugf("reading region water usage effectiveness from: %v", dataFile)
+	if err := easycsv.NewReader(strings.NewReader(string(regionWaterFile))).ReadAll(&records); err != nil {
+		return nil, &data.MalformedDataError{File: dataFile, Err: err}
+	}
+
+	data := make(map[string]Water)
+	for _, record := range records {
+		data[record.Region] = Water{
+			Region:                  record.Region,
+			Location:                record.Location,
+			WaterUsageEffectiveness: decimal.NewFromFloat(record.WaterUsageEffectiveness),
+			Source:                  record.Source,
+		}
+	}
+	return data, nil
+}
@@ -3,11 +3,11 @@ package coefficients
 import (
 	"encoding/json"
 	"reflect"
+	"sync"
 
 	"github.com/carboniferio/carbonifer/internal/data"
 	"github.com/carboniferio/carbonifer/internal/providers"
 	"github.com/shopspring/decimal"
-	log "github.com/sirupsen/logrus"
 )
 
 // Coefficients is a struct that contains the coefficients for the energy estimation
@@ -16,9 +16,15 @@ type Coefficients struct {
 	CPUMaxWh       decimal.Decimal `json:"cpu_max_wh"`
 	StorageHddWhTb decimal.Decimal `json:"storage_hdd_wh_tb"`
 	StorageSsdWhTb decimal.Decimal `json:"storage_ssd_wh_tb"`
-	NetworkingWhGb decimal.Decimal `json:"networking_wh_gb"`
-	MemoryWhGb     decimal.Decimal `json:"memory_wh_gb"`
-	PueAverage     decimal.Decimal `json:"pue_average"`
+	// Archive storage classes are assumed to be kept on media that only draw power when read, such as tape
+	StorageArchiveWhTb decimal.Decimal `json:"storage_archive_wh_tb"`
+	NetworkingWhGb     decimal.Decimal `json:"networking_wh_gb"`
+	MemoryWhGb         decimal.Decimal `json:"memory_wh_gb"`
+	PueAverage         decimal.Decimal `json:"pue_average"`
+	// Embodied emissions of a host, shared by its vCPUs over its lifespan
+	EmbodiedHostKgCO2eq   decimal.Decimal `json:"embodied_host_kgco2eq"`
+	EmbodiedHostVCPUs     decimal.Decimal `json:"embodied_host_vcpus"`
+	EmbodiedLifespanYears decimal.Decimal `json:"embodied_lifespan_years"`
 }
 
 // CoefficientsProviders is a struct that contains the coefficients for the energy estimation per provider
@@ -28,23 +34,41 @@ type CoefficientsProviders struct {
 	Azure Coefficients `json:"Azure"`
 }
 
-var coefficientsPerProviders *CoefficientsProviders
+// dataFileKey identifies a data file read from a data directory, empty for the embedded ones
+type dataFileKey struct {
+	DataPath string
+	File     string
+}
+
+// coefficientsPerProviders are the coefficients, per data directory
+var coefficientsPerProviders = map[string]*CoefficientsProviders{}
+
+// coefficientsMutex guards the data files read by the package
+var coefficientsMutex sync.Mutex
 
 // GetEnergyCoefficients returns the coefficients for the energy estimation
-func GetEnergyCoefficients() *CoefficientsProviders {
-	if coefficientsPerProviders == nil {
-		energyCoefFile := data.ReadDataFile("energy_coefficients.json")
-		err := json.Unmarshal(energyCoefFile, &coefficientsPerProviders)
-		if err != nil {
-			log.Fatal(err)
-		}
+func GetEnergyCoefficients(dataPath string) (*CoefficientsProviders, error) {
+	coefficientsMutex.Lock()
+	defer coefficientsMutex.Unlock()
+	if coefs, ok := coefficientsPerProviders[dataPath]; ok {
+		return coefs, nil
+	}
+	energyCoefFile, err := data.ReadDataFile(dataPath, "energy_coefficients.json")
+	if err != nil {
+		return nil, err
+	}
+	var coefs *CoefficientsProviders
+	err = json.Unmarshal(energyCoefFile, &coefs)
+	if err != nil {
+		return nil, &data.MalformedDataError{File: "energy_coefficients.json", Err: err}
 	}
-	return coefficientsPerProviders
+	coefficientsPerProviders[dataPath] = coefs
+	return coefs, nil
 }
 
 // GetByProvider returns the coefficients for the energy estimation of a provider
 func (cps *CoefficientsProviders) GetByProvider(provider providers.Provider) Coefficients {
-	return coefficientsPerProviders.getByProviderName(provider.String())
+	return cps.getByProviderName(provider.String())
 }
 
 func (cps *CoefficientsProviders) getByProviderName(name string) Coefficients {
@@ -0,0 +1,80 @@
+package estimate
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/carboniferio/carbonifer/internal/estimate/estimation"
+	"github.com/carboniferio/carbonifer/internal/resources"
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+// Hours in a month and in a year, as for the units of time of the estimations
+var (
+	hoursPerMonth = decimal.NewFromInt(24 * 30)
+	hoursPerYear  = decimal.NewFromInt(24 * 365)
+)
+
+// CompareResources estimates one instance of each compute resource, sorted from the lowest hourly emissions.
+// Resources that cannot be estimated are kept last, with the reason. The unit of time must be the hour.
+func CompareResources(cfg *viper.Viper, computeResources []resources.ComputeResource) (estimation.ComparisonReport, error) {
+	if unitTime := cfg.Get("unit.time").(string); unitTime != "h" {
+		return estimation.ComparisonReport{}, errors.Errorf("Comparison is made per hour, unit.time must be 'h', not '%v'", unitTime)
+	}
+	unitCarbon := cfg.Get("unit.carbon").(string)
+	report := estimation.ComparisonReport{
+		UnitPower:                cfg.Get("unit.power").(string),
+		UnitCarbonEmissionsHour:  fmt.Sprintf("%sCO2eq/h", unitCarbon),
+		UnitCarbonEmissionsMonth: fmt.Sprintf("%sCO2eq/m", unitCarbon),
+		UnitCarbonEmissionsYear:  fmt.Sprintf("%sCO2eq/y", unitCarbon),
+		DateTime:                 time.Now(),
+		Candidates:               []estimation.ComparisonCandidate{},
+	}
+	for _, resource := range computeResources {
+		report.Candidates = append(report.Candidates, compareResource(cfg, resource))
+	}
+	sort.SliceStable(report.Candidates, func(i, j int) bool {
+		a, b := report.Candidates[i], report.Candidates[j]
+		if (a.Error == "") != (b.Error == "") {
+			return a.Error == ""
+		}
+		return a.CarbonEmissionsPerHour.LessThan(b.CarbonEmissionsPerHour)
+	})
+	return report, nil
+}
+
+func compareResource(cfg *viper.Viper, resource resources.ComputeResource) estimation.ComparisonCandidate {
+	candidate := estimation.ComparisonCandidate{
+		Name:     resource.Identification.Name,
+		Provider: resource.Identification.Provider,
+		Region:   resource.Identification.Region,
+		VCPUs:    resource.Specs.VCPUs,
+		MemoryMb: resource.Specs.MemoryMb,
+	}
+	estimationResource, err := EstimateResource(cfg, resource)
+	if err != nil {
+		logrus.Warnf("Cannot estimate %v in %v: %v", candidate.Name, candidate.Region, err)
+		candidate.Error = err.Error()
+		return candidate
+	}
+
+	perHour := estimationResource.CarbonEmissions
+
+	candidate.CPUPlatform = estimationResource.CPUPlatform
+	candidate.Power = estimationResource.Power
+	candidate.CarbonEmissionsPerHour = perHour.RoundFloor(10)
+	candidate.CarbonEmissionsPerMonth = perHour.Mul(hoursPerMonth).RoundFloor(10)
+	candidate.CarbonEmissionsPerYear = perHour.Mul(hoursPerYear).RoundFloor(10)
+	if candidate.VCPUs > 0 {
+		candidate.CarbonEmissionsPerVCPU = perHour.Div(decimal.NewFromInt32(candidate.VCPUs)).RoundFloor(10)
+	}
+	if candidate.MemoryMb > 0 {
+		memoryGb := decimal.NewFromInt32(candidate.MemoryMb).Div(decimal.NewFromInt(1024))
+		candidate.CarbonEmissionsPerMemoryGb = perHour.Div(memoryGb).RoundFloor(10)
+	}
+	return candidate
+}
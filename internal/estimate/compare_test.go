@@ -0,0 +1,72 @@
+package estimate
+
+import (
+	"testing"
+
+	"github.com/carboniferio/carbonifer/internal/providers"
+	"github.com/carboniferio/carbonifer/internal/resources"
+	_ "github.com/carboniferio/carbonifer/internal/testutils"
+	"github.com/shopspring/decimal"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func comparedResource(name string, region string) resources.ComputeResource {
+	return resources.ComputeResource{
+		Identification: &resources.ResourceIdentification{
+			Address:           "gcp_instance." + name,
+			Name:              name,
+			ResourceType:      "gcp_instance",
+			Provider:          providers.GCP,
+			Region:            region,
+			ReplicationFactor: 1,
+			Count:             1,
+		},
+		Specs: &resources.ComputeResourceSpecs{
+			VCPUs:    2,
+			MemoryMb: 4096,
+		},
+	}
+}
+
+func TestCompareResources(t *testing.T) {
+	viper.Set("unit.carbon", "g")
+	viper.Set("unit.time", "h")
+
+	report, err := CompareResources(viper.GetViper(), []resources.ComputeResource{
+		comparedResource("n1-standard-2", "us-central1"),
+		comparedResource("n1-standard-2", "mars-east1"),
+		comparedResource("n1-standard-2", "europe-west9"),
+	})
+	assert.NoError(t, err)
+
+	assert.Equal(t, "gCO2eq/h", report.UnitCarbonEmissionsHour)
+	assert.Equal(t, "gCO2eq/m", report.UnitCarbonEmissionsMonth)
+	assert.Len(t, report.Candidates, 3)
+
+	// Sorted from the lowest emissions, errors last
+	assert.Equal(t, "europe-west9", report.Candidates[0].Region)
+	assert.Equal(t, "us-central1", report.Candidates[1].Region)
+	assert.Equal(t, "mars-east1", report.Candidates[2].Region)
+	assert.True(t, report.Candidates[0].CarbonEmissionsPerHour.LessThan(report.Candidates[1].CarbonEmissionsPerHour))
+	assert.NotEmpty(t, report.Candidates[2].Error)
+	assert.True(t, report.Candidates[2].CarbonEmissionsPerHour.IsZero())
+
+	// Same power in both regions
+	assert.Equal(t, report.Candidates[0].Power.String(), report.Candidates[1].Power.String())
+
+	candidate := report.Candidates[1]
+	perHour := candidate.CarbonEmissionsPerHour
+	assert.Equal(t, perHour.Mul(decimal.NewFromInt(24*30)).StringFixed(4), candidate.CarbonEmissionsPerMonth.StringFixed(4))
+	assert.Equal(t, perHour.Mul(decimal.NewFromInt(24*365)).StringFixed(4), candidate.CarbonEmissionsPerYear.StringFixed(4))
+	assert.Equal(t, perHour.Div(decimal.NewFromInt(2)).StringFixed(4), candidate.CarbonEmissionsPerVCPU.StringFixed(4))
+	assert.Equal(t, perHour.Div(decimal.NewFromInt(4)).StringFixed(4), candidate.CarbonEmissionsPerMemoryGb.StringFixed(4))
+}
+
+func TestCompareResources_UnitTime(t *testing.T) {
+	viper.Set("unit.time", "m")
+	defer viper.Set("unit.time", "h")
+
+	_, err := CompareResources(viper.GetViper(), []resources.ComputeResource{comparedResource("n1-standard-2", "us-central1")})
+	assert.Error(t, err)
+}
@@ -1,6 +1,7 @@
 package estimate
 
 import (
+	"errors"
 	"fmt"
 	"sort"
 	"time"
@@ -16,23 +17,42 @@ import (
 )
 
 // EstimateResources estimates the power and carbon emissions of a list of resources
-func EstimateResources(resourceList map[string]resources.Resource) estimation.EstimationReport {
+func EstimateResources(cfg *viper.Viper, resourceList map[string]resources.Resource) estimation.EstimationReport {
 
 	var estimationResources []estimation.EstimationResource
 	var unsupportedResources []resources.Resource
 	estimationTotal := estimation.EstimationTotal{
-		Power:           decimal.Zero,
-		CarbonEmissions: decimal.Zero,
-		ResourcesCount:  decimal.Zero,
+		Power:                       decimal.Zero,
+		CarbonEmissions:             decimal.Zero,
+		ResourcesCount:              decimal.Zero,
+		NetworkPower:                decimal.Zero,
+		NetworkCarbonEmissions:      decimal.Zero,
+		MarketBasedEmissions:        decimal.NewNullDecimal(decimal.Zero),
+		NetworkMarketBasedEmissions: decimal.NewNullDecimal(decimal.Zero),
+		EmbodiedEmissions:           decimal.Zero,
+		Water:                       decimal.NewNullDecimal(decimal.Zero),
+		PowerRange:                  estimation.EstimationRange{Low: decimal.Zero, High: decimal.Zero},
+		CarbonEmissionsRange:        estimation.EstimationRange{Low: decimal.Zero, High: decimal.Zero},
 	}
 	for _, resource := range resourceList {
-		estimationResource, uerr := EstimateResource(resource)
-		if uerr != nil {
-			logrus.Warnf("Skipping unsupported provider %v: %v.%v", uerr.Provider, resource.GetIdentification().ResourceType, resource.GetIdentification().Name)
+		estimationResource, err := EstimateResource(cfg, resource)
+		if err != nil {
+			var unsupportedProviderErr *providers.UnsupportedProviderError
+			if errors.As(err, &unsupportedProviderErr) {
+				logrus.Warnf("Skipping unsupported provider %v: %v.%v", unsupportedProviderErr.Provider, resource.GetIdentification().ResourceType, resource.GetIdentification().Name)
+				continue
+			}
+			// Keep the resource in the report, with the reason it could not be estimated
+			logrus.Warnf("Cannot estimate %v: %v", resource.GetAddress(), err)
+			estimationResources = append(estimationResources, *estimateFailed(resource, err))
+			continue
 		}
 
 		if resource.IsSupported() {
 			estimationResources = append(estimationResources, *estimationResource)
+			if len(estimationResource.Warnings) > 0 {
+				estimationTotal.ResourcesWithDefaults++
+			}
 		} else {
 			unsupportedResources = append(unsupportedResources, resource)
 		}
@@ -40,24 +60,45 @@ func EstimateResources(resourceList map[string]resources.Resource) estimation.Es
 		estimationTotal.Power = estimationTotal.Power.Add(estimationResource.Power.Mul(estimationResource.TotalCount))
 		estimationTotal.CarbonEmissions = estimationTotal.CarbonEmissions.Add(estimationResource.CarbonEmissions.Mul(estimationResource.TotalCount))
 		estimationTotal.ResourcesCount = estimationTotal.ResourcesCount.Add(estimationResource.TotalCount)
+		estimationTotal.MarketBasedEmissions = addNullDecimal(estimationTotal.MarketBasedEmissions, mulNullDecimal(estimationResource.MarketBasedEmissions, estimationResource.TotalCount))
+		estimationTotal.EmbodiedEmissions = estimationTotal.EmbodiedEmissions.Add(estimationResource.EmbodiedEmissions.Mul(estimationResource.TotalCount))
+		estimationTotal.Water = addNullDecimal(estimationTotal.Water, mulNullDecimal(estimationResource.Water, estimationResource.TotalCount))
+		estimationTotal.PowerRange.Low = estimationTotal.PowerRange.Low.Add(estimationResource.PowerRange.Low.Mul(estimationResource.TotalCountRange.Low))
+		estimationTotal.PowerRange.High = estimationTotal.PowerRange.High.Add(estimationResource.PowerRange.High.Mul(estimationResource.TotalCountRange.High))
+		estimationTotal.CarbonEmissionsRange.Low = estimationTotal.CarbonEmissionsRange.Low.Add(estimationResource.CarbonEmissionsRange.Low.Mul(estimationResource.TotalCountRange.Low))
+		estimationTotal.CarbonEmissionsRange.High = estimationTotal.CarbonEmissionsRange.High.Add(estimationResource.CarbonEmissionsRange.High.Mul(estimationResource.TotalCountRange.High))
+		if !estimationResource.NetworkPower.IsZero() {
+			estimationTotal.PowerRange.Low = estimationTotal.PowerRange.Low.Add(estimationResource.NetworkPowerRange.Low)
+			estimationTotal.PowerRange.High = estimationTotal.PowerRange.High.Add(estimationResource.NetworkPowerRange.High)
+			estimationTotal.CarbonEmissionsRange.Low = estimationTotal.CarbonEmissionsRange.Low.Add(estimationResource.NetworkCarbonEmissionsRange.Low)
+			estimationTotal.CarbonEmissionsRange.High = estimationTotal.CarbonEmissionsRange.High.Add(estimationResource.NetworkCarbonEmissionsRange.High)
+			estimationTotal.Power = estimationTotal.Power.Add(estimationResource.NetworkPower)
+			estimationTotal.CarbonEmissions = estimationTotal.CarbonEmissions.Add(estimationResource.NetworkCarbonEmissions)
+			estimationTotal.NetworkPower = estimationTotal.NetworkPower.Add(estimationResource.NetworkPower)
+			estimationTotal.NetworkCarbonEmissions = estimationTotal.NetworkCarbonEmissions.Add(estimationResource.NetworkCarbonEmissions)
+			estimationTotal.MarketBasedEmissions = addNullDecimal(estimationTotal.MarketBasedEmissions, estimationResource.NetworkMarketBasedEmissions)
+			estimationTotal.NetworkMarketBasedEmissions = addNullDecimal(estimationTotal.NetworkMarketBasedEmissions, estimationResource.NetworkMarketBasedEmissions)
+		}
 	}
 
 	return estimation.EstimationReport{
 		Info: estimation.EstimationInfo{
-			UnitTime:                viper.Get("unit.time").(string),
-			UnitWattTime:            fmt.Sprintf("%s%s", viper.Get("unit.power"), viper.Get("unit.time")),
-			UnitCarbonEmissionsTime: fmt.Sprintf("%sCO2eq/%s", viper.Get("unit.carbon"), viper.Get("unit.time")),
+			UnitTime:                cfg.Get("unit.time").(string),
+			UnitWattTime:            fmt.Sprintf("%s%s", cfg.Get("unit.power"), cfg.Get("unit.time")),
+			UnitCarbonEmissionsTime: fmt.Sprintf("%sCO2eq/%s", cfg.Get("unit.carbon"), cfg.Get("unit.time")),
+			UnitWaterTime:           fmt.Sprintf("L/%s", cfg.Get("unit.time")),
 			DateTime:                time.Now(),
 			InfoByProvider: map[providers.Provider]estimation.InfoByProvider{
 				providers.GCP: {
-					AverageCPUUsage: viper.GetFloat64("provider.gcp.avg_cpu_use"),
-					AverageGPUUsage: viper.GetFloat64("provider.gcp.avg_gpu_use"),
+					AverageCPUUsage: cfg.GetFloat64("provider.gcp.avg_cpu_use"),
+					AverageGPUUsage: cfg.GetFloat64("provider.gcp.avg_gpu_use"),
 				},
 				providers.AWS: {
-					AverageCPUUsage: viper.GetFloat64("provider.gcp.avg_cpu_use"),
-					AverageGPUUsage: viper.GetFloat64("provider.gcp.avg_gpu_use"),
+					AverageCPUUsage: cfg.GetFloat64("provider.gcp.avg_cpu_use"),
+					AverageGPUUsage: cfg.GetFloat64("provider.gcp.avg_gpu_use"),
 				},
 			},
+			Uncertainty: estimate.GetUncertainty(cfg),
 		},
 		Resources:            estimationResources,
 		UnsupportedResources: unsupportedResources,
@@ -73,27 +114,72 @@ func SortEstimations(resources *[]estimation.EstimationResource) {
 	})
 }
 
+// ExplainResource returns the steps of the estimation of a resource, none if it is not a compute resource that can be estimated
+func ExplainResource(cfg *viper.Viper, resource resources.Resource) ([]estimation.FormulaStep, error) {
+	if _, ok := resource.(resources.ComputeResource); !ok {
+		return []estimation.FormulaStep{}, nil
+	}
+	switch resource.GetIdentification().Provider {
+	case providers.AWS, providers.GCP:
+		return estimate.ExplainSupportedResource(cfg, resource)
+	default:
+		return nil, &providers.UnsupportedProviderError{Provider: resource.GetIdentification().Provider.String()}
+	}
+}
+
 // EstimateResource estimates the power and carbon emissions of a resource
-func EstimateResource(resource resources.Resource) (*estimation.EstimationResource, *providers.UnsupportedProviderError) {
+func EstimateResource(cfg *viper.Viper, resource resources.Resource) (*estimation.EstimationResource, error) {
 	if !resource.IsSupported() {
 		return estimateNotSupported(resource.(resources.UnsupportedResource)), nil
 	}
+	if failedResource, ok := resource.(resources.FailedResource); ok {
+		return nil, failedResource.Err
+	}
 	switch resource.GetIdentification().Provider {
 	case providers.AWS:
-		return estimate.EstimateSupportedResource(resource), nil
+		return estimate.EstimateSupportedResource(cfg, resource)
 	case providers.GCP:
-		return estimate.EstimateSupportedResource(resource), nil
+		return estimate.EstimateSupportedResource(cfg, resource)
 	default:
 		return nil, &providers.UnsupportedProviderError{Provider: resource.GetIdentification().Provider.String()}
 	}
 }
 
 func estimateNotSupported(resource resources.UnsupportedResource) *estimation.EstimationResource {
+	return &estimation.EstimationResource{
+		Resource:             resource,
+		Power:                decimal.Zero,
+		CarbonEmissions:      decimal.Zero,
+		MarketBasedEmissions: decimal.NewNullDecimal(decimal.Zero),
+		Water:                decimal.NewNullDecimal(decimal.Zero),
+		AverageCPUUsage:      decimal.Zero,
+		TotalCount:           decimal.Zero,
+	}
+}
+
+// addNullDecimal returns the sum of two values, null if one of them is null
+func addNullDecimal(a decimal.NullDecimal, b decimal.NullDecimal) decimal.NullDecimal {
+	if !a.Valid || !b.Valid {
+		return decimal.NullDecimal{}
+	}
+	return decimal.NewNullDecimal(a.Decimal.Add(b.Decimal))
+}
+
+// mulNullDecimal returns the product of a value by a factor, null if the value is null
+func mulNullDecimal(a decimal.NullDecimal, factor decimal.Decimal) decimal.NullDecimal {
+	if !a.Valid {
+		return decimal.NullDecimal{}
+	}
+	return decimal.NewNullDecimal(a.Decimal.Mul(factor))
+}
+
+func estimateFailed(resource resources.Resource, err error) *estimation.EstimationResource {
 	return &estimation.EstimationResource{
 		Resource:        resource,
 		Power:           decimal.Zero,
 		CarbonEmissions: decimal.Zero,
 		AverageCPUUsage: decimal.Zero,
 		TotalCount:      decimal.Zero,
+		Error:           err.Error(),
 	}
 }
@@ -8,25 +8,135 @@ import (
 	"github.com/carboniferio/carbonifer/internal/providers"
 	"github.com/carboniferio/carbonifer/internal/providers/gcp"
 	"github.com/carboniferio/carbonifer/internal/resources"
+	"github.com/pkg/errors"
 	"github.com/shopspring/decimal"
+	log "github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
 )
 
-func estimateWattCPU(resource *resources.ComputeResource) decimal.Decimal {
+// Policies to estimate the CPU power of machine types that can run on several CPU platforms, set in config `provider.gcp.cpu_platform_policy`
+const (
+	CPUPlatformPolicyFlat    = "flat"    // flat coefficients of the provider, whatever the platforms
+	CPUPlatformPolicyAverage = "average" // average of the watts of the platforms
+	CPUPlatformPolicyMin     = "min"     // platform with the lowest max watts
+	CPUPlatformPolicyMax     = "max"     // platform with the highest max watts
+)
+
+func estimateWattCPU(cfg *viper.Viper, resource *resources.ComputeResource, coefs *coefficients.CoefficientsProviders) (decimal.Decimal, error) {
 	provider := resource.Identification.Provider
 	// Get average CPU usage
-	averageCPUUse := decimal.NewFromFloat(viper.GetFloat64(fmt.Sprintf("provider.%s.avg_cpu_use", provider.String())))
+	averageCPUUse, _ := AverageCPUUse(cfg, resource)
 
 	var avgWatts decimal.Decimal
 	// Average Watts = Min Watts + Avg vCPU Utilization * (Max Watts - Min Watts)
-	cpuPlatform := resource.Specs.CPUType
-	if cpuPlatform != "" && resource.Identification.Provider == providers.GCP {
-		cpuPlatform := gcp.GetCPUWatt(strings.ToLower(cpuPlatform))
+	cpuPlatform, _, err := cpuPlatformWatt(cfg, resource)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	if cpuPlatform != nil {
 		avgWatts = cpuPlatform.MinWatts.Add(averageCPUUse.Mul(cpuPlatform.MaxWatts.Sub(cpuPlatform.MinWatts)))
 	} else {
-		minWH := coefficients.GetEnergyCoefficients().GetByProvider(provider).CPUMinWh
-		maxWh := coefficients.GetEnergyCoefficients().GetByProvider(provider).CPUMaxWh
+		minWH := coefs.GetByProvider(provider).CPUMinWh
+		maxWh := coefs.GetByProvider(provider).CPUMaxWh
 		avgWatts = minWH.Add(averageCPUUse.Mul(maxWh.Sub(minWH)))
 	}
-	return avgWatts.Mul(decimal.NewFromInt32(resource.Specs.VCPUs))
+	vCPUs := decimal.NewFromInt32(resource.Specs.VCPUs)
+	if resource.Specs.VCPUs == 0 && !resource.Specs.FractionalVCPUs.IsZero() {
+		vCPUs = resource.Specs.FractionalVCPUs
+	}
+	return avgWatts.Mul(vCPUs), nil
+}
+
+// AverageCPUUse returns the average CPU utilization of the resource and its source, the provider default if not set on the resource
+func AverageCPUUse(cfg *viper.Viper, resource *resources.ComputeResource) (decimal.Decimal, string) {
+	if resource.Specs.AvgCPUUseSource != "" {
+		return resource.Specs.AvgCPUUse, resource.Specs.AvgCPUUseSource
+	}
+	provider := resource.Identification.Provider
+	return decimal.NewFromFloat(cfg.GetFloat64(fmt.Sprintf("provider.%s.avg_cpu_use", provider.String()))), resources.SourceConfig
+}
+
+// CPUPlatform returns the CPU platform assumed to estimate the CPU power of the resource, empty if the flat coefficients of the provider are used
+func CPUPlatform(cfg *viper.Viper, resource *resources.ComputeResource) (string, error) {
+	_, platform, err := cpuPlatformWatt(cfg, resource)
+	return platform, err
+}
+
+// cpuPlatformWatt returns the watts per vCPU of the CPU platform assumed for the resource and its name:
+// the CPU platform of the resource if known, or else the one of its machine type platforms chosen by the CPU platform policy.
+// It returns nil if the flat coefficients of the provider are to be used.
+func cpuPlatformWatt(cfg *viper.Viper, resource *resources.ComputeResource) (*gcp.CPUWatt, string, error) {
+	if resource.Identification.Provider != providers.GCP {
+		return nil, "", nil
+	}
+	if resource.Specs.CPUType != "" {
+		cpuWatt, err := gcp.GetCPUWatt(cfg.GetString("data.path"), resource.Specs.CPUType)
+		if err != nil {
+			return nil, "", err
+		}
+		if cpuWatt.Architecture != "" {
+			return &cpuWatt, cpuWatt.Architecture, nil
+		}
+		log.Debugf("Unknown CPU platform '%v' of %v", resource.Specs.CPUType, resource.Identification.Address)
+	}
+
+	policy := cfg.GetString("provider.gcp.cpu_platform_policy")
+	switch policy {
+	case "", CPUPlatformPolicyFlat:
+		return nil, "", nil
+	case CPUPlatformPolicyAverage, CPUPlatformPolicyMin, CPUPlatformPolicyMax:
+	default:
+		return nil, "", errors.Errorf("Invalid provider.gcp.cpu_platform_policy '%v', must be one of %v, %v, %v, %v", policy, CPUPlatformPolicyFlat, CPUPlatformPolicyAverage, CPUPlatformPolicyMin, CPUPlatformPolicyMax)
+	}
+
+	platforms := []gcp.CPUWatt{}
+	for _, cpuType := range resource.Specs.CPUTypes {
+		cpuWatt, err := gcp.GetCPUWatt(cfg.GetString("data.path"), cpuType)
+		if err != nil {
+			return nil, "", err
+		}
+		if cpuWatt.Architecture == "" {
+			log.Debugf("Unknown CPU platform '%v' of %v", cpuType, resource.Identification.Address)
+			continue
+		}
+		platforms = append(platforms, cpuWatt)
+	}
+	if len(platforms) == 0 {
+		return nil, "", nil
+	}
+
+	chosen := platforms[0]
+	switch policy {
+	case CPUPlatformPolicyAverage:
+		if len(platforms) == 1 {
+			break
+		}
+		names := []string{}
+		minWatts := decimal.Zero
+		maxWatts := decimal.Zero
+		for _, platform := range platforms {
+			names = append(names, platform.Architecture)
+			minWatts = minWatts.Add(platform.MinWatts)
+			maxWatts = maxWatts.Add(platform.MaxWatts)
+		}
+		count := decimal.NewFromInt(int64(len(platforms)))
+		chosen = gcp.CPUWatt{
+			Architecture: fmt.Sprintf("average of %v", strings.Join(names, ", ")),
+			MinWatts:     minWatts.Div(count),
+			MaxWatts:     maxWatts.Div(count),
+		}
+	case CPUPlatformPolicyMin:
+		for _, platform := range platforms[1:] {
+			if platform.MaxWatts.LessThan(chosen.MaxWatts) {
+				chosen = platform
+			}
+		}
+	case CPUPlatformPolicyMax:
+		for _, platform := range platforms[1:] {
+			if platform.MaxWatts.GreaterThan(chosen.MaxWatts) {
+				chosen = platform
+			}
+		}
+	}
+	return &chosen, chosen.Architecture, nil
 }
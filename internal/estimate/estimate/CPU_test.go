@@ -0,0 +1,79 @@
+package estimate
+
+import (
+	"testing"
+
+	"github.com/carboniferio/carbonifer/internal/estimate/coefficients"
+	"github.com/carboniferio/carbonifer/internal/providers"
+	"github.com/carboniferio/carbonifer/internal/resources"
+	_ "github.com/carboniferio/carbonifer/internal/testutils"
+	"github.com/shopspring/decimal"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func multiPlatformResource(provider providers.Provider, cpuType string) *resources.ComputeResource {
+	return &resources.ComputeResource{
+		Identification: &resources.ResourceIdentification{
+			Name:     "multi-platform",
+			Count:    1,
+			Provider: provider,
+		},
+		Specs: &resources.ComputeResourceSpecs{
+			VCPUs:    2,
+			CPUType:  cpuType,
+			CPUTypes: []string{"Skylake", "Broadwell", "Haswell", "AMD EPYC Rome", "Ice Lake"},
+		},
+	}
+}
+
+func TestCPUPlatform(t *testing.T) {
+	defer viper.Set("provider.gcp.cpu_platform_policy", "flat")
+	tests := []struct {
+		name     string
+		policy   string
+		resource *resources.ComputeResource
+		want     string
+	}{
+		{"flat", "flat", multiPlatformResource(providers.GCP, ""), ""},
+		{"average", "average", multiPlatformResource(providers.GCP, ""), "average of Skylake, Broadwell, Haswell, EPYC 2nd Gen"},
+		{"min", "min", multiPlatformResource(providers.GCP, ""), "EPYC 2nd Gen"},
+		{"max", "max", multiPlatformResource(providers.GCP, ""), "Haswell"},
+		{"CPU platform of the resource", "max", multiPlatformResource(providers.GCP, "Intel Broadwell"), "Broadwell"},
+		{"unknown CPU platform of the resource", "min", multiPlatformResource(providers.GCP, "Intel Sapphire Rapids"), "EPYC 2nd Gen"},
+		{"AWS", "max", multiPlatformResource(providers.AWS, ""), ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Set("provider.gcp.cpu_platform_policy", tt.policy)
+			got, err := CPUPlatform(viper.GetViper(), tt.resource)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	viper.Set("provider.gcp.cpu_platform_policy", "median")
+	_, err := CPUPlatform(viper.GetViper(), multiPlatformResource(providers.GCP, ""))
+	assert.ErrorContains(t, err, "Invalid provider.gcp.cpu_platform_policy 'median'")
+}
+
+func TestEstimateWattCPU_Policies(t *testing.T) {
+	defer viper.Set("provider.gcp.cpu_platform_policy", "flat")
+	coefs, err := coefficients.GetEnergyCoefficients(viper.GetString("data.path"))
+	assert.NoError(t, err)
+	resource := multiPlatformResource(providers.GCP, "")
+	resource.Specs.AvgCPUUse = decimal.NewFromFloat(0.5)
+	resource.Specs.AvgCPUUseSource = resources.SourceConfig
+
+	watts := map[string]decimal.Decimal{}
+	for _, policy := range []string{"flat", "average", "min", "max"} {
+		viper.Set("provider.gcp.cpu_platform_policy", policy)
+		watts[policy], err = estimateWattCPU(viper.GetViper(), resource, coefs)
+		assert.NoError(t, err)
+	}
+	// EPYC 2nd Gen: 2 vCPUs * (0.4742621527777778 + 0.5 * (1.5751872939814815 - 0.4742621527777778))
+	assert.Equal(t, "2.0494494467592593", watts["min"].String())
+	assert.True(t, watts["min"].LessThan(watts["average"]))
+	assert.True(t, watts["average"].LessThan(watts["max"]))
+	assert.False(t, watts["flat"].Equal(watts["average"]))
+}
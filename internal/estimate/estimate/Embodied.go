@@ -0,0 +1,45 @@
+package estimate
+
+import (
+	"github.com/carboniferio/carbonifer/internal/estimate/coefficients"
+	"github.com/carboniferio/carbonifer/internal/resources"
+	"github.com/shopspring/decimal"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+// Source: https://www.cloudcarbonfootprint.org/docs/methodology/#embodied-emissions
+// in gCO2eq per hour: embodied emissions of the host * (vCPUs of the instance / vCPUs of the host) / hours of the host lifespan
+func estimateEmbodiedEmissions(resource *resources.ComputeResource, coefs *coefficients.CoefficientsProviders) decimal.Decimal {
+	vCPUs := decimal.NewFromInt32(resource.Specs.VCPUs)
+	if resource.Specs.VCPUs == 0 && !resource.Specs.FractionalVCPUs.IsZero() {
+		vCPUs = resource.Specs.FractionalVCPUs
+	}
+	providerCoefs := coefs.GetByProvider(resource.Identification.Provider)
+	if vCPUs.IsZero() || providerCoefs.EmbodiedHostVCPUs.IsZero() || providerCoefs.EmbodiedLifespanYears.IsZero() {
+		return decimal.Zero
+	}
+	lifespanHours := providerCoefs.EmbodiedLifespanYears.Mul(decimal.NewFromInt(24 * 365))
+	embodied := providerCoefs.EmbodiedHostKgCO2eq.Mul(decimal.NewFromInt(1000)).Mul(vCPUs).Div(providerCoefs.EmbodiedHostVCPUs).Div(lifespanHours)
+	usageRatio := resource.Identification.UsageRatio
+	if !usageRatio.IsZero() {
+		embodied = embodied.Mul(usageRatio)
+	}
+	log.Debugf("%v.%v Embodied emissions in gCO2eq/h: %v", resource.Identification.ResourceType, resource.Identification.Name, embodied)
+	return embodied
+}
+
+// toCarbonUnit converts emissions in gCO2eq per hour to the configured carbon and time units
+func toCarbonUnit(cfg *viper.Viper, gramsPerHour decimal.Decimal) decimal.Decimal {
+	emissions := gramsPerHour
+	if cfg.Get("unit.carbon").(string) == "kg" {
+		emissions = emissions.Div(decimal.NewFromInt(1000))
+	}
+	if cfg.Get("unit.time").(string) == "m" {
+		emissions = emissions.Mul(decimal.NewFromInt(24 * 30))
+	}
+	if cfg.Get("unit.time").(string) == "y" {
+		emissions = emissions.Mul(decimal.NewFromInt(24 * 365))
+	}
+	return emissions
+}
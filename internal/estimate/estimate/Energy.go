@@ -5,20 +5,27 @@ import (
 	"github.com/carboniferio/carbonifer/internal/resources"
 	"github.com/shopspring/decimal"
 	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
 )
 
 // Source: https://www.cloudcarbonfootprint.org/docs/methodology/#appendix-i-energy-coefficients
 // in Watt Hour
-func estimateWattHour(resource *resources.ComputeResource) decimal.Decimal {
-	cpuEstimationInWh := estimateWattCPU(resource)
+func estimateWattHour(cfg *viper.Viper, resource *resources.ComputeResource, coefs *coefficients.CoefficientsProviders) (decimal.Decimal, error) {
+	cpuEstimationInWh, err := estimateWattCPU(cfg, resource, coefs)
+	if err != nil {
+		return decimal.Zero, err
+	}
 	log.Debugf("%v.%v CPU in Wh: %v", resource.Identification.ResourceType, resource.Identification.Name, cpuEstimationInWh)
-	memoryEstimationInWH := estimateWattMem(resource)
+	memoryEstimationInWH := estimateWattMem(resource, coefs)
 	log.Debugf("%v.%v Memory in Wh: %v", resource.Identification.ResourceType, resource.Identification.Name, memoryEstimationInWH)
-	storageInWh := estimateWattStorage(resource)
+	storageInWh := estimateWattStorage(resource, coefs)
 	log.Debugf("%v.%v Storage in Wh: %v", resource.Identification.ResourceType, resource.Identification.Name, storageInWh)
-	gpuEstimationInWh := EstimateWattGPU(resource)
+	gpuEstimationInWh, err := EstimateWattGPU(cfg, resource)
+	if err != nil {
+		return decimal.Zero, err
+	}
 	log.Debugf("%v.%v GPUs in Wh: %v", resource.Identification.ResourceType, resource.Identification.Name, gpuEstimationInWh)
-	pue := coefficients.GetEnergyCoefficients().GCP.PueAverage
+	pue := coefs.GCP.PueAverage
 	log.Debugf("%v.%v PUE %v", resource.Identification.ResourceType, resource.Identification.Name, pue)
 	rawWattEstimate := decimal.Sum(
 		cpuEstimationInWh,
@@ -31,6 +38,11 @@ func estimateWattHour(resource *resources.ComputeResource) decimal.Decimal {
 		replicationFactor = 1
 	}
 	wattEstimate := pue.Mul(rawWattEstimate).Mul(decimal.NewFromInt32(replicationFactor))
+	usageRatio := resource.Identification.UsageRatio
+	if !usageRatio.IsZero() {
+		log.Debugf("%v.%v Usage ratio %v", resource.Identification.ResourceType, resource.Identification.Name, usageRatio)
+		wattEstimate = wattEstimate.Mul(usageRatio)
+	}
 	log.Debugf("%v.%v Energy in Wh: %v", resource.Identification.ResourceType, resource.Identification.Name, wattEstimate)
-	return wattEstimate
+	return wattEstimate, nil
 }
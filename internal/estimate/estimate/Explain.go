@@ -0,0 +1,154 @@
+package estimate
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/carboniferio/carbonifer/internal/estimate/coefficients"
+	"github.com/carboniferio/carbonifer/internal/estimate/estimation"
+	"github.com/carboniferio/carbonifer/internal/providers"
+	"github.com/carboniferio/carbonifer/internal/resources"
+	"github.com/shopspring/decimal"
+	"github.com/spf13/viper"
+)
+
+const energyCoefficientsFile = "energy_coefficients.json"
+
+// ExplainSupportedResource returns the steps of the estimation of the energy and carbon emissions of a resource per hour,
+// with the coefficients used and their sources, as computed by EstimateSupportedResource
+func ExplainSupportedResource(cfg *viper.Viper, resource resources.Resource) ([]estimation.FormulaStep, error) {
+	computeResource := resource.(resources.ComputeResource)
+	provider := computeResource.Identification.Provider
+	coefs, err := coefficients.GetEnergyCoefficients(cfg.GetString("data.path"))
+	if err != nil {
+		return nil, err
+	}
+	providerCoefs := coefs.GetByProvider(provider)
+	providerKey := strings.ToLower(provider.String())
+	steps := []estimation.FormulaStep{}
+
+	// CPU
+	cpuWh, err := estimateWattCPU(cfg, &computeResource, coefs)
+	if err != nil {
+		return nil, err
+	}
+	averageCPUUse, averageCPUUseSource := AverageCPUUse(cfg, &computeResource)
+	cpuPlatform, cpuPlatformName, err := cpuPlatformWatt(cfg, &computeResource)
+	if err != nil {
+		return nil, err
+	}
+	vCPUs := decimal.NewFromInt32(computeResource.Specs.VCPUs)
+	if computeResource.Specs.VCPUs == 0 && !computeResource.Specs.FractionalVCPUs.IsZero() {
+		vCPUs = computeResource.Specs.FractionalVCPUs
+	}
+	minWatts, maxWatts := providerCoefs.CPUMinWh, providerCoefs.CPUMaxWh
+	cpuSources := []string{fmt.Sprintf("%v (%v.cpu_min_wh, cpu_max_wh)", energyCoefficientsFile, provider.String())}
+	if cpuPlatform != nil {
+		minWatts, maxWatts = cpuPlatform.MinWatts, cpuPlatform.MaxWatts
+		cpuSources = []string{fmt.Sprintf("gcp_watt_cpu.csv (%v)", cpuPlatformName)}
+	}
+	cpuSources = append(cpuSources, utilizationSource("avg_cpu_use", providerKey, averageCPUUseSource))
+	steps = append(steps, estimation.FormulaStep{
+		Name:    "CPU",
+		Formula: fmt.Sprintf("%v vCPUs * (%v + %v * (%v - %v))", vCPUs, minWatts, averageCPUUse, maxWatts, minWatts),
+		Value:   cpuWh,
+		Unit:    "Wh",
+		Sources: cpuSources,
+	})
+
+	// Memory
+	memoryWh := estimateWattMem(&computeResource, coefs)
+	steps = append(steps, estimation.FormulaStep{
+		Name:    "Memory",
+		Formula: fmt.Sprintf("%v MB / 1024 * %v", computeResource.Specs.MemoryMb, providerCoefs.MemoryWhGb),
+		Value:   memoryWh,
+		Unit:    "Wh",
+		Sources: []string{fmt.Sprintf("%v (%v.memory_wh_gb)", energyCoefficientsFile, provider.String())},
+	})
+
+	// Storage
+	storageWh := estimateWattStorage(&computeResource, coefs)
+	storageFormula := fmt.Sprintf("%v GB SSD * %v / 1024 + %v GB HDD * %v / 1024",
+		computeResource.Specs.SsdStorage, providerCoefs.StorageSsdWhTb, computeResource.Specs.HddStorage, providerCoefs.StorageHddWhTb)
+	storageSources := fmt.Sprintf("%v (%v.storage_ssd_wh_tb, storage_hdd_wh_tb)", energyCoefficientsFile, provider.String())
+	if !computeResource.Specs.ArchiveStorage.IsZero() {
+		storageFormula += fmt.Sprintf(" + %v GB archive * %v / 1024", computeResource.Specs.ArchiveStorage, providerCoefs.StorageArchiveWhTb)
+		storageSources = fmt.Sprintf("%v (%v.storage_ssd_wh_tb, storage_hdd_wh_tb, storage_archive_wh_tb)", energyCoefficientsFile, provider.String())
+	}
+	steps = append(steps, estimation.FormulaStep{
+		Name:    "Storage",
+		Formula: storageFormula,
+		Value:   storageWh,
+		Unit:    "Wh",
+		Sources: []string{storageSources},
+	})
+
+	// GPUs
+	gpuWh, err := EstimateWattGPU(cfg, &computeResource)
+	if err != nil {
+		return nil, err
+	}
+	if len(computeResource.Specs.GpuTypes) > 0 {
+		averageGPUUse, averageGPUUseSource := AverageGPUUse(cfg, &computeResource)
+		gpuFormulas := []string{}
+		for _, gpuType := range computeResource.Specs.GpuTypes {
+			gpuWatt, err := providers.GetGPUWatt(cfg.GetString("data.path"), gpuType)
+			if err != nil {
+				return nil, err
+			}
+			gpuFormulas = append(gpuFormulas, fmt.Sprintf("%v (%v + %v * (%v - %v))", gpuType, gpuWatt.MinWatts, averageGPUUse, gpuWatt.MaxWatts, gpuWatt.MinWatts))
+		}
+		steps = append(steps, estimation.FormulaStep{
+			Name:    "GPUs",
+			Formula: strings.Join(gpuFormulas, " + "),
+			Value:   gpuWh,
+			Unit:    "Wh",
+			Sources: []string{"gpu_watt.csv", utilizationSource("avg_gpu_use", providerKey, averageGPUUseSource)},
+		})
+	}
+
+	// Energy of the resource
+	wattHour, err := estimateWattHour(cfg, &computeResource, coefs)
+	if err != nil {
+		return nil, err
+	}
+	replicationFactor := computeResource.Identification.ReplicationFactor
+	if replicationFactor == 0 {
+		replicationFactor = 1
+	}
+	energyFormula := fmt.Sprintf("(%v + %v + %v + %v) * PUE %v * replication factor %v",
+		cpuWh, memoryWh, storageWh, gpuWh, coefs.GCP.PueAverage, replicationFactor)
+	if !computeResource.Identification.UsageRatio.IsZero() {
+		energyFormula += fmt.Sprintf(" * usage ratio %v", computeResource.Identification.UsageRatio)
+	}
+	steps = append(steps, estimation.FormulaStep{
+		Name:    "Energy",
+		Formula: energyFormula,
+		Value:   wattHour,
+		Unit:    "Wh",
+		// The PUE of GCP is used for all providers
+		Sources: []string{fmt.Sprintf("%v (GCP.pue_average)", energyCoefficientsFile)},
+	})
+
+	// Carbon emissions of the resource
+	regionEmissions, err := coefficients.RegionEmission(cfg.GetString("data.path"), provider, computeResource.Identification.Region)
+	if err != nil {
+		return nil, err
+	}
+	steps = append(steps, estimation.FormulaStep{
+		Name:    "Carbon emissions",
+		Formula: fmt.Sprintf("%v Wh / 1000 * %v gCO2eq/kWh (%v)", wattHour, regionEmissions.GridCarbonIntensity, computeResource.Identification.Region),
+		Value:   wattHour.Div(decimal.NewFromInt(1000)).Mul(regionEmissions.GridCarbonIntensity),
+		Unit:    "gCO2eq/h",
+		Sources: []string{fmt.Sprintf("%v_co2_region.csv (%v)", providerKey, computeResource.Identification.Region)},
+	})
+	return steps, nil
+}
+
+// utilizationSource returns where an average utilization comes from, the config key if it is the provider default
+func utilizationSource(name string, providerKey string, source string) string {
+	if source == resources.SourceConfig {
+		return fmt.Sprintf("config provider.%v.%v", providerKey, name)
+	}
+	return fmt.Sprintf("%v %v", source, name)
+}
@@ -0,0 +1,63 @@
+package estimate
+
+import (
+	"testing"
+
+	"github.com/carboniferio/carbonifer/internal/providers"
+	"github.com/carboniferio/carbonifer/internal/resources"
+	_ "github.com/carboniferio/carbonifer/internal/testutils"
+	"github.com/shopspring/decimal"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExplainSupportedResource(t *testing.T) {
+	viper.Set("unit.power", "W")
+	viper.Set("unit.time", "h")
+	viper.Set("unit.carbon", "g")
+	resource := resources.ComputeResource{
+		Identification: &resources.ResourceIdentification{
+			Address:           "google_compute_instance.explained",
+			Name:              "explained",
+			Provider:          providers.GCP,
+			Region:            "europe-west9",
+			Count:             1,
+			ReplicationFactor: 1,
+		},
+		Specs: &resources.ComputeResourceSpecs{
+			VCPUs:      2,
+			MemoryMb:   4096,
+			SsdStorage: decimal.NewFromInt(100),
+			HddStorage: decimal.Zero,
+			GpuTypes:   []string{"nvidia-tesla-t4"},
+		},
+	}
+
+	steps, err := ExplainSupportedResource(viper.GetViper(), resource)
+	assert.NoError(t, err)
+	names := []string{}
+	for _, step := range steps {
+		names = append(names, step.Name)
+		assert.NotEmpty(t, step.Formula)
+		assert.NotEmpty(t, step.Sources)
+	}
+	assert.Equal(t, []string{"CPU", "Memory", "Storage", "GPUs", "Energy", "Carbon emissions"}, names)
+	assert.Contains(t, steps[0].Formula, "2 vCPUs * (")
+	assert.Contains(t, steps[3].Sources, "gpu_watt.csv")
+	assert.Equal(t, []string{"gcp_co2_region.csv (europe-west9)"}, steps[5].Sources)
+
+	// The steps lead to the estimation of the resource
+	estimation, err := EstimateSupportedResource(viper.GetViper(), resource)
+	assert.NoError(t, err)
+	assert.Equal(t, estimation.Power.StringFixed(6), steps[4].Value.StringFixed(6))
+	assert.Equal(t, estimation.CarbonEmissions.StringFixed(6), steps[5].Value.StringFixed(6))
+}
+
+func TestExplainSupportedResource_CPUPlatform(t *testing.T) {
+	resource := multiPlatformResource(providers.GCP, "Skylake")
+	resource.Identification.Region = "europe-west9"
+
+	steps, err := ExplainSupportedResource(viper.GetViper(), *resource)
+	assert.NoError(t, err)
+	assert.Contains(t, steps[0].Sources, "gcp_watt_cpu.csv (Skylake)")
+}
@@ -0,0 +1,45 @@
+package estimate
+
+import (
+	"fmt"
+
+	"github.com/carboniferio/carbonifer/internal/estimate/coefficients"
+	"github.com/carboniferio/carbonifer/internal/resources"
+	"github.com/shopspring/decimal"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+// carbonFreeEnergyRatio returns the share of the electricity of a resource covered by carbon free energy, between 0 and 1:
+// the renewable coverage set in config `market_based.renewable_coverage` for the provider, or else the carbon free energy of the region.
+// It is null if neither is known.
+func carbonFreeEnergyRatio(cfg *viper.Viper, resource *resources.ComputeResource) decimal.NullDecimal {
+	provider := resource.Identification.Provider
+	configKey := fmt.Sprintf("market_based.renewable_coverage.%v", provider.String())
+	if cfg.Get(configKey) != nil && cfg.GetString(configKey) != "" {
+		return decimal.NewNullDecimal(clampRatio(decimal.NewFromFloat(cfg.GetFloat64(configKey))))
+	}
+	regionCarbonFreeEnergy, err := coefficients.RegionCarbonFreeEnergy(cfg.GetString("data.path"), provider, resource.Identification.Region)
+	if err != nil {
+		log.Debugf("No carbon free energy for %v, market-based emissions not reported: %v", resource.Identification.Address, err)
+		return decimal.NullDecimal{}
+	}
+	return decimal.NewNullDecimal(clampRatio(regionCarbonFreeEnergy.CarbonFreeEnergy))
+}
+
+// marketBasedGridCarbonIntensity returns the grid carbon intensity of the electricity not covered by carbon free energy,
+// null if the carbon free energy is unknown
+func marketBasedGridCarbonIntensity(gridCarbonIntensity decimal.Decimal, carbonFreeEnergy decimal.NullDecimal) decimal.NullDecimal {
+	if !carbonFreeEnergy.Valid {
+		return decimal.NullDecimal{}
+	}
+	return decimal.NewNullDecimal(gridCarbonIntensity.Mul(decimal.NewFromInt(1).Sub(carbonFreeEnergy.Decimal)))
+}
+
+// marketBasedEmissions returns the market-based emissions of a power, null if the market-based grid carbon intensity is unknown
+func marketBasedEmissions(watt decimal.Decimal, marketBasedGridCarbonIntensity decimal.NullDecimal) decimal.NullDecimal {
+	if !marketBasedGridCarbonIntensity.Valid {
+		return decimal.NullDecimal{}
+	}
+	return decimal.NewNullDecimal(watt.Mul(marketBasedGridCarbonIntensity.Decimal).RoundFloor(10))
+}
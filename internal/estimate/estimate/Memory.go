@@ -6,7 +6,7 @@ import (
 	"github.com/shopspring/decimal"
 )
 
-func estimateWattMem(resource *resources.ComputeResource) decimal.Decimal {
+func estimateWattMem(resource *resources.ComputeResource, coefs *coefficients.CoefficientsProviders) decimal.Decimal {
 	provider := resource.Identification.Provider
-	return decimal.NewFromInt32(resource.Specs.MemoryMb).Div(decimal.NewFromInt32(1024)).Mul(coefficients.GetEnergyCoefficients().GetByProvider(provider).MemoryWhGb)
+	return decimal.NewFromInt32(resource.Specs.MemoryMb).Div(decimal.NewFromInt32(1024)).Mul(coefs.GetByProvider(provider).MemoryWhGb)
 }
@@ -0,0 +1,21 @@
+package estimate
+
+import (
+	"github.com/carboniferio/carbonifer/internal/estimate/coefficients"
+	"github.com/carboniferio/carbonifer/internal/resources"
+	"github.com/shopspring/decimal"
+	log "github.com/sirupsen/logrus"
+)
+
+// Source: https://www.cloudcarbonfootprint.org/docs/methodology/#networking
+// in Watt Hour, the monthly data transfer being spread over the hours of a month
+func estimateWattNetwork(resource *resources.ComputeResource, coefs *coefficients.CoefficientsProviders) decimal.Decimal {
+	if resource.Specs.NetworkEgressGb.IsZero() {
+		return decimal.Zero
+	}
+	providerCoefs := coefs.GetByProvider(resource.Identification.Provider)
+	monthlyWh := resource.Specs.NetworkEgressGb.Mul(providerCoefs.NetworkingWhGb).Mul(providerCoefs.PueAverage)
+	networkWh := monthlyWh.Div(decimal.NewFromInt(24 * 30))
+	log.Debugf("%v.%v Networking in Wh: %v", resource.Identification.ResourceType, resource.Identification.Name, networkWh)
+	return networkWh
+}
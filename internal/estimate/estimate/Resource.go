@@ -11,43 +11,58 @@ import (
 )
 
 // EstimateSupportedResource gets the carbon emissions of a GCP resource
-func EstimateSupportedResource(resource resources.Resource) *estimation.EstimationResource {
+func EstimateSupportedResource(cfg *viper.Viper, resource resources.Resource) (*estimation.EstimationResource, error) {
 
 	var computeResource resources.ComputeResource = resource.(resources.ComputeResource)
-	// Electric power used per unit of time
-	avgWatt := estimateWattHour(&computeResource) // Watt hour
-	if viper.Get("unit.power").(string) == "kW" {
-		avgWatt = avgWatt.Div(decimal.NewFromInt(1000))
-	}
-	if viper.Get("unit.time").(string) == "m" {
-		avgWatt = avgWatt.Mul(decimal.NewFromInt(24 * 30))
+	coefs, err := coefficients.GetEnergyCoefficients(cfg.GetString("data.path"))
+	if err != nil {
+		return nil, err
 	}
-	if viper.Get("unit.time").(string) == "y" {
-		avgWatt = avgWatt.Mul(decimal.NewFromInt(24 * 365))
+	// Electric power used per unit of time
+	wattHour, err := estimateWattHour(cfg, &computeResource, coefs)
+	if err != nil {
+		return nil, err
 	}
+	avgWatt := toPowerUnit(cfg, wattHour) // Watt hour
+	// Electric power used by the declared data transfer of the whole resource
+	networkWattHour := estimateWattNetwork(&computeResource, coefs)
+	networkWatt := toPowerUnit(cfg, networkWattHour)
 	avgWattStr := avgWatt.String()
+	// Water used on site per unit of time
+	water := toWaterUnit(cfg, estimateWater(cfg, &computeResource, wattHour, coefs.GetByProvider(computeResource.Identification.Provider).PueAverage))
+	// Low and high estimations
+	lowWattHour, highWattHour, err := estimateWattHourRange(cfg, &computeResource, coefs)
+	if err != nil {
+		return nil, err
+	}
+	lowNetworkWattHour, highNetworkWattHour := estimateWattNetworkRange(cfg, networkWattHour, coefs.GetByProvider(computeResource.Identification.Provider).PueAverage)
 
 	// Regional grid emission per unit of time
-	regionEmissions, err := coefficients.RegionEmission(resource.GetIdentification().Provider, resource.GetIdentification().Region) // gCO2eq /kWh
+	regionEmissions, err := coefficients.RegionEmission(cfg.GetString("data.path"), resource.GetIdentification().Provider, resource.GetIdentification().Region) // gCO2eq /kWh
 	if err != nil {
-		log.Fatalf("Error while getting region emissions for %v: %v", resource.GetAddress(), err)
+		return nil, err
 	}
-	if viper.Get("unit.power").(string) == "W" {
+	if cfg.Get("unit.power").(string) == "W" {
 		regionEmissions.GridCarbonIntensity = regionEmissions.GridCarbonIntensity.Div(decimal.NewFromInt(1000))
 	}
-	if viper.Get("unit.time").(string) == "m" {
+	if cfg.Get("unit.time").(string) == "m" {
 		regionEmissions.GridCarbonIntensity = regionEmissions.GridCarbonIntensity.Mul(decimal.NewFromInt(24 * 30))
 	}
-	if viper.Get("unit.time").(string) == "y" {
+	if cfg.Get("unit.time").(string) == "y" {
 		regionEmissions.GridCarbonIntensity = regionEmissions.GridCarbonIntensity.Mul(decimal.NewFromInt(24 * 365))
 	}
-	if viper.Get("unit.carbon").(string) == "kg" {
+	if cfg.Get("unit.carbon").(string) == "kg" {
 		regionEmissions.GridCarbonIntensity = regionEmissions.GridCarbonIntensity.Div(decimal.NewFromInt(1000))
 	}
 
 	// Carbon Emissions
 	carbonEmissionPerTime := avgWatt.Mul(regionEmissions.GridCarbonIntensity)
 	carbonEmissionPerTimeStr := carbonEmissionPerTime.String()
+	networkCarbonEmissionPerTime := networkWatt.Mul(regionEmissions.GridCarbonIntensity)
+	lowGridCarbonIntensity, highGridCarbonIntensity := gridCarbonIntensityRange(cfg, regionEmissions.GridCarbonIntensity)
+	// Market-based emissions, electricity covered by carbon free energy excluded
+	carbonFreeEnergy := carbonFreeEnergyRatio(cfg, &computeResource)
+	marketBasedGridCarbonIntensity := marketBasedGridCarbonIntensity(regionEmissions.GridCarbonIntensity, carbonFreeEnergy)
 
 	log.Debugf(
 		"estimating resource %v.%v (%v): %v %v%v * %v %vCO2/%v%v = %v %vCO2/%v%v * %v",
@@ -55,16 +70,16 @@ func EstimateSupportedResource(resource resources.Resource) *estimation.Estimati
 		computeResource.Identification.Name,
 		regionEmissions.Region,
 		avgWattStr,
-		viper.Get("unit.power").(string),
-		viper.Get("unit.time").(string),
+		cfg.Get("unit.power").(string),
+		cfg.Get("unit.time").(string),
 		regionEmissions.GridCarbonIntensity,
-		viper.Get("unit.carbon").(string),
-		viper.Get("unit.power").(string),
-		viper.Get("unit.time").(string),
+		cfg.Get("unit.carbon").(string),
+		cfg.Get("unit.power").(string),
+		cfg.Get("unit.time").(string),
 		carbonEmissionPerTimeStr,
-		viper.Get("unit.carbon").(string),
-		viper.Get("unit.power").(string),
-		viper.Get("unit.time").(string),
+		cfg.Get("unit.carbon").(string),
+		cfg.Get("unit.power").(string),
+		cfg.Get("unit.time").(string),
 		resource.GetIdentification().Count,
 	)
 
@@ -72,15 +87,79 @@ func EstimateSupportedResource(resource resources.Resource) *estimation.Estimati
 		log.Println("my_cluster_autoscaled")
 	}
 
+	averageCPUUse, averageCPUUseSource := AverageCPUUse(cfg, &computeResource)
+	cpuPlatform, err := CPUPlatform(cfg, &computeResource)
+	if err != nil {
+		return nil, err
+	}
 	count := int64(computeResource.Identification.Count)
 	replicationFactor := int64(computeResource.Identification.ReplicationFactor)
 
 	est := &estimation.EstimationResource{
-		Resource:        &computeResource,
-		Power:           avgWatt.RoundFloor(10),
-		CarbonEmissions: carbonEmissionPerTime.RoundFloor(10),
-		AverageCPUUsage: decimal.NewFromFloat(viper.GetFloat64("provider.gcp.avg_cpu_use")).RoundFloor(10),
-		TotalCount:      decimal.NewFromInt(count * replicationFactor),
+		Resource:              &computeResource,
+		Power:                 avgWatt.RoundFloor(10),
+		CarbonEmissions:       carbonEmissionPerTime.RoundFloor(10),
+		MarketBasedEmissions:  marketBasedEmissions(avgWatt, marketBasedGridCarbonIntensity),
+		CarbonFreeEnergy:      carbonFreeEnergy,
+		AverageCPUUsage:       averageCPUUse.RoundFloor(10),
+		TotalCount:            decimal.NewFromInt(count * replicationFactor),
+		AverageCPUUsageSource: averageCPUUseSource,
+		CPUPlatform:           cpuPlatform,
+	}
+	est.NetworkMarketBasedEmissions = marketBasedEmissions(networkWatt, marketBasedGridCarbonIntensity)
+	est.DataQuality, est.Warnings = computeResource.Specs.DataQuality()
+	if len(computeResource.Specs.GpuTypes) > 0 {
+		averageGPUUse, averageGPUUseSource := AverageGPUUse(cfg, &computeResource)
+		est.AverageGPUUsage = averageGPUUse.RoundFloor(10)
+		est.AverageGPUUsageSource = averageGPUUseSource
+	}
+	if !networkWatt.IsZero() {
+		est.NetworkPower = networkWatt.RoundFloor(10)
+		est.NetworkCarbonEmissions = networkCarbonEmissionPerTime.RoundFloor(10)
+	}
+
+	lowWatt := toPowerUnit(cfg, lowWattHour)
+	highWatt := toPowerUnit(cfg, highWattHour)
+	est.PowerRange = estimation.EstimationRange{Low: lowWatt.RoundFloor(10), High: highWatt.RoundFloor(10)}
+	est.CarbonEmissionsRange = estimation.EstimationRange{
+		Low:  lowWatt.Mul(lowGridCarbonIntensity).RoundFloor(10),
+		High: highWatt.Mul(highGridCarbonIntensity).RoundFloor(10),
+	}
+	est.EmbodiedEmissions = toCarbonUnit(cfg, estimateEmbodiedEmissions(&computeResource, coefs)).RoundFloor(10)
+	if water.Valid {
+		est.Water = decimal.NewNullDecimal(water.Decimal.RoundFloor(10))
+	}
+
+	est.TotalCountRange = estimation.EstimationRange{Low: est.TotalCount, High: est.TotalCount}
+	if computeResource.Identification.CountHigh != 0 {
+		est.TotalCountRange = estimation.EstimationRange{
+			Low:  decimal.NewFromInt(computeResource.Identification.CountLow * replicationFactor),
+			High: decimal.NewFromInt(computeResource.Identification.CountHigh * replicationFactor),
+		}
+	}
+	if !networkWatt.IsZero() {
+		lowNetworkWatt := toPowerUnit(cfg, lowNetworkWattHour)
+		highNetworkWatt := toPowerUnit(cfg, highNetworkWattHour)
+		est.NetworkPowerRange = estimation.EstimationRange{Low: lowNetworkWatt.RoundFloor(10), High: highNetworkWatt.RoundFloor(10)}
+		est.NetworkCarbonEmissionsRange = estimation.EstimationRange{
+			Low:  lowNetworkWatt.Mul(lowGridCarbonIntensity).RoundFloor(10),
+			High: highNetworkWatt.Mul(highGridCarbonIntensity).RoundFloor(10),
+		}
+	}
+	return est, nil
+}
+
+// toPowerUnit converts an average power in Watt hour to the configured power and time units
+func toPowerUnit(cfg *viper.Viper, wattHour decimal.Decimal) decimal.Decimal {
+	power := wattHour
+	if cfg.Get("unit.power").(string) == "kW" {
+		power = power.Div(decimal.NewFromInt(1000))
+	}
+	if cfg.Get("unit.time").(string) == "m" {
+		power = power.Mul(decimal.NewFromInt(24 * 30))
+	}
+	if cfg.Get("unit.time").(string) == "y" {
+		power = power.Mul(decimal.NewFromInt(24 * 365))
 	}
-	return est
+	return power
 }
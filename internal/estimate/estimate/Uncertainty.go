@@ -0,0 +1,90 @@
+package estimate
+
+import (
+	"github.com/carboniferio/carbonifer/internal/estimate/coefficients"
+	"github.com/carboniferio/carbonifer/internal/estimate/estimation"
+	"github.com/carboniferio/carbonifer/internal/resources"
+	"github.com/shopspring/decimal"
+	"github.com/spf13/viper"
+)
+
+// GetUncertainty returns the variations of the assumptions set in config `uncertainty`
+func GetUncertainty(cfg *viper.Viper) estimation.Uncertainty {
+	return estimation.Uncertainty{
+		Utilization:              cfg.GetFloat64("uncertainty.utilization"),
+		AvgAutoscalerSizePercent: cfg.GetFloat64("uncertainty.avg_autoscaler_size_percent"),
+		PUE:                      cfg.GetFloat64("uncertainty.pue"),
+		GridCarbonIntensity:      cfg.GetFloat64("uncertainty.grid_carbon_intensity"),
+	}
+}
+
+// estimateWattHourRange returns the low and high energy of a resource in Watt Hour,
+// with utilization and PUE varying within their uncertainty
+func estimateWattHourRange(cfg *viper.Viper, resource *resources.ComputeResource, coefs *coefficients.CoefficientsProviders) (decimal.Decimal, decimal.Decimal, error) {
+	uncertainty := GetUncertainty(cfg)
+	utilizationDelta := decimal.NewFromFloat(uncertainty.Utilization)
+	pue := coefs.GCP.PueAverage
+	low, err := estimateWattHour(cfg, withUtilizationDelta(cfg, resource, utilizationDelta.Neg()), coefs)
+	if err != nil {
+		return decimal.Zero, decimal.Zero, err
+	}
+	high, err := estimateWattHour(cfg, withUtilizationDelta(cfg, resource, utilizationDelta), coefs)
+	if err != nil {
+		return decimal.Zero, decimal.Zero, err
+	}
+	return low.Mul(pueFactor(pue, -uncertainty.PUE)), high.Mul(pueFactor(pue, uncertainty.PUE)), nil
+}
+
+// estimateWattNetworkRange returns the low and high energy of the data transfer of a resource in Watt Hour,
+// with PUE varying within its uncertainty
+func estimateWattNetworkRange(cfg *viper.Viper, networkWattHour decimal.Decimal, pue decimal.Decimal) (decimal.Decimal, decimal.Decimal) {
+	uncertainty := GetUncertainty(cfg)
+	return networkWattHour.Mul(pueFactor(pue, -uncertainty.PUE)), networkWattHour.Mul(pueFactor(pue, uncertainty.PUE))
+}
+
+// gridCarbonIntensityRange returns the low and high grid carbon intensities, varying within their uncertainty
+func gridCarbonIntensityRange(cfg *viper.Viper, gridCarbonIntensity decimal.Decimal) (decimal.Decimal, decimal.Decimal) {
+	delta := decimal.NewFromFloat(GetUncertainty(cfg).GridCarbonIntensity)
+	low := gridCarbonIntensity.Mul(decimal.NewFromInt(1).Sub(delta))
+	if low.IsNegative() {
+		low = decimal.Zero
+	}
+	return low, gridCarbonIntensity.Mul(decimal.NewFromInt(1).Add(delta))
+}
+
+// withUtilizationDelta returns a copy of the resource, its average CPU and GPU utilizations shifted by delta, between 0 and 1
+func withUtilizationDelta(cfg *viper.Viper, resource *resources.ComputeResource, delta decimal.Decimal) *resources.ComputeResource {
+	specs := *resource.Specs
+	averageCPUUse, averageCPUUseSource := AverageCPUUse(cfg, resource)
+	specs.AvgCPUUse = clampRatio(averageCPUUse.Add(delta))
+	specs.AvgCPUUseSource = averageCPUUseSource
+	averageGPUUse, averageGPUUseSource := AverageGPUUse(cfg, resource)
+	specs.AvgGPUUse = clampRatio(averageGPUUse.Add(delta))
+	specs.AvgGPUUseSource = averageGPUUseSource
+	return &resources.ComputeResource{
+		Identification: resource.Identification,
+		Specs:          &specs,
+	}
+}
+
+// pueFactor returns the ratio between the PUE varied by a relative delta and the PUE, a PUE being at least 1
+func pueFactor(pue decimal.Decimal, delta float64) decimal.Decimal {
+	if pue.IsZero() {
+		return decimal.NewFromInt(1)
+	}
+	variedPue := pue.Mul(decimal.NewFromFloat(1 + delta))
+	if variedPue.LessThan(decimal.NewFromInt(1)) {
+		variedPue = decimal.NewFromInt(1)
+	}
+	return variedPue.Div(pue)
+}
+
+func clampRatio(ratio decimal.Decimal) decimal.Decimal {
+	if ratio.IsNegative() {
+		return decimal.Zero
+	}
+	if ratio.GreaterThan(decimal.NewFromInt(1)) {
+		return decimal.NewFromInt(1)
+	}
+	return ratio
+}
@@ -0,0 +1,41 @@
+package estimate
+
+import (
+	"github.com/carboniferio/carbonifer/internal/estimate/coefficients"
+	"github.com/carboniferio/carbonifer/internal/resources"
+	"github.com/shopspring/decimal"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+// estimateWater returns the water used on site by a resource in Litres per hour:
+// energy of the IT equipment (without PUE) * water usage effectiveness (WUE) of the region.
+// It is null if the WUE of the region is unknown.
+func estimateWater(cfg *viper.Viper, resource *resources.ComputeResource, wattHour decimal.Decimal, pue decimal.Decimal) decimal.NullDecimal {
+	regionWater, err := coefficients.RegionWater(cfg.GetString("data.path"), resource.Identification.Provider, resource.Identification.Region)
+	if err != nil {
+		log.Debugf("No water usage effectiveness for %v, water not reported: %v", resource.Identification.Address, err)
+		return decimal.NullDecimal{}
+	}
+	if wattHour.IsZero() || pue.IsZero() {
+		return decimal.NewNullDecimal(decimal.Zero)
+	}
+	itKiloWattHour := wattHour.Div(pue).Div(decimal.NewFromInt(1000))
+	water := itKiloWattHour.Mul(regionWater.WaterUsageEffectiveness)
+	log.Debugf("%v.%v Water in L/h: %v", resource.Identification.ResourceType, resource.Identification.Name, water)
+	return decimal.NewNullDecimal(water)
+}
+
+// toWaterUnit converts water in Litres per hour to the configured time unit
+func toWaterUnit(cfg *viper.Viper, litresPerHour decimal.NullDecimal) decimal.NullDecimal {
+	if !litresPerHour.Valid {
+		return litresPerHour
+	}
+	if cfg.Get("unit.time").(string) == "m" {
+		return decimal.NewNullDecimal(litresPerHour.Decimal.Mul(decimal.NewFromInt(24 * 30)))
+	}
+	if cfg.Get("unit.time").(string) == "y" {
+		return decimal.NewNullDecimal(litresPerHour.Decimal.Mul(decimal.NewFromInt(24 * 365)))
+	}
+	return litresPerHour
+}
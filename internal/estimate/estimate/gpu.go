@@ -11,17 +11,28 @@ import (
 )
 
 // EstimateWattGPU estimates the power consumption of a GPU resource
-func EstimateWattGPU(resource *resources.ComputeResource) decimal.Decimal {
+func EstimateWattGPU(cfg *viper.Viper, resource *resources.ComputeResource) (decimal.Decimal, error) {
 	// Get average GPU usage
-	provider := strings.ToLower(resource.Identification.Provider.String())
-	averageCPUUse := decimal.NewFromFloat(viper.GetFloat64(fmt.Sprintf("provider.%s.avg_gpu_use", provider)))
+	averageGPUUse, _ := AverageGPUUse(cfg, resource)
 
 	avgWattsTotal := decimal.Zero
 	// Average Watts = Min Watts + Avg GPU Utilization * (Max Watts - Min Watts)
 	for _, gpuType := range resource.Specs.GpuTypes {
-		gpuWatt := providers.GetGPUWatt(gpuType)
-		avgWatts := gpuWatt.MinWatts.Add(averageCPUUse.Mul(gpuWatt.MaxWatts.Sub(gpuWatt.MinWatts)))
+		gpuWatt, err := providers.GetGPUWatt(cfg.GetString("data.path"), gpuType)
+		if err != nil {
+			return decimal.Zero, err
+		}
+		avgWatts := gpuWatt.MinWatts.Add(averageGPUUse.Mul(gpuWatt.MaxWatts.Sub(gpuWatt.MinWatts)))
 		avgWattsTotal = avgWattsTotal.Add(avgWatts)
 	}
-	return avgWattsTotal
+	return avgWattsTotal, nil
+}
+
+// AverageGPUUse returns the average GPU utilization of the resource and its source, the provider default if not set on the resource
+func AverageGPUUse(cfg *viper.Viper, resource *resources.ComputeResource) (decimal.Decimal, string) {
+	if resource.Specs.AvgGPUUseSource != "" {
+		return resource.Specs.AvgGPUUse, resource.Specs.AvgGPUUseSource
+	}
+	provider := strings.ToLower(resource.Identification.Provider.String())
+	return decimal.NewFromFloat(cfg.GetFloat64(fmt.Sprintf("provider.%s.avg_gpu_use", provider))), resources.SourceConfig
 }
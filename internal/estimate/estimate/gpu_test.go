@@ -7,6 +7,7 @@ import (
 	"github.com/carboniferio/carbonifer/internal/resources"
 	_ "github.com/carboniferio/carbonifer/internal/testutils"
 	"github.com/shopspring/decimal"
+	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -57,7 +58,8 @@ func Test_estimateWattGPU(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := EstimateWattGPU(tt.args.resource)
+			got, err := EstimateWattGPU(viper.GetViper(), tt.args.resource)
+			assert.NoError(t, err)
 			assert.Equal(t, tt.want, got)
 
 		})
@@ -6,11 +6,13 @@ import (
 	"github.com/shopspring/decimal"
 )
 
-func estimateWattStorage(resource *resources.ComputeResource) decimal.Decimal {
+func estimateWattStorage(resource *resources.ComputeResource, coefs *coefficients.CoefficientsProviders) decimal.Decimal {
 	provider := resource.Identification.Provider
-	storageSsdWhGb := coefficients.GetEnergyCoefficients().GetByProvider(provider).StorageSsdWhTb.Div(decimal.NewFromInt32(1024))
-	storageHddWhGb := coefficients.GetEnergyCoefficients().GetByProvider(provider).StorageHddWhTb.Div(decimal.NewFromInt32(1024))
+	storageSsdWhGb := coefs.GetByProvider(provider).StorageSsdWhTb.Div(decimal.NewFromInt32(1024))
+	storageHddWhGb := coefs.GetByProvider(provider).StorageHddWhTb.Div(decimal.NewFromInt32(1024))
+	storageArchiveWhGb := coefs.GetByProvider(provider).StorageArchiveWhTb.Div(decimal.NewFromInt32(1024))
 	storageSSDWh := resource.Specs.SsdStorage.Mul(storageSsdWhGb)
 	storageHddWh := resource.Specs.HddStorage.Mul(storageHddWhGb)
-	return storageSSDWh.Add(storageHddWh)
+	storageArchiveWh := resource.Specs.ArchiveStorage.Mul(storageArchiveWhGb)
+	return storageSSDWh.Add(storageHddWh).Add(storageArchiveWh)
 }
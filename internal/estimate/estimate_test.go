@@ -4,6 +4,7 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/carboniferio/carbonifer/internal/estimate/coefficients"
 	"github.com/carboniferio/carbonifer/internal/estimate/estimation"
 	"github.com/carboniferio/carbonifer/internal/providers"
 	"github.com/carboniferio/carbonifer/internal/resources"
@@ -80,6 +81,56 @@ var resourceGCPInstanceGroup = resources.ComputeResource{
 	},
 }
 
+var resourceGCPLoadBalancer = resources.ComputeResource{
+	Identification: &resources.ResourceIdentification{
+		Address:           "google_compute_global_forwarding_rule.lb-1",
+		Name:              "lb-1",
+		ResourceType:      "type-1",
+		Provider:          providers.GCP,
+		Region:            "europe-west9",
+		ReplicationFactor: 1,
+		Count:             1,
+	},
+	Specs: &resources.ComputeResourceSpecs{
+		NetworkEgressGb: decimal.NewFromInt(720),
+	},
+}
+
+var resourceGCPComputeBusy = resources.ComputeResource{
+	Identification: &resources.ResourceIdentification{
+		Address:           "google_compute_instance.machine-busy",
+		Name:              "machine-busy",
+		ResourceType:      "type-1",
+		Provider:          providers.GCP,
+		Region:            "europe-west9",
+		ReplicationFactor: 1,
+		Count:             1,
+	},
+	Specs: &resources.ComputeResourceSpecs{
+		VCPUs:           2,
+		MemoryMb:        4096,
+		AvgCPUUse:       decimal.NewFromFloat(0.9),
+		AvgCPUUseSource: resources.SourceLabel,
+	},
+}
+
+var resourceGCPServerless = resources.ComputeResource{
+	Identification: &resources.ResourceIdentification{
+		Address:           "google_cloudfunctions2_function.function-1",
+		Name:              "function-1",
+		ResourceType:      "type-1",
+		Provider:          providers.GCP,
+		Region:            "europe-west9",
+		ReplicationFactor: 1,
+		Count:             1,
+		UsageRatio:        decimal.NewFromFloat(0.5),
+	},
+	Specs: &resources.ComputeResourceSpecs{
+		FractionalVCPUs: decimal.NewFromFloat(0.5),
+		MemoryMb:        1024,
+	},
+}
+
 func TestEstimateResource(t *testing.T) {
 	avgCPUUse := viper.GetFloat64("provider.gcp.avg_cpu_use")
 	type args struct {
@@ -123,15 +174,162 @@ func TestEstimateResource(t *testing.T) {
 				TotalCount:      decimal.NewFromInt(3),
 			},
 		},
+		{
+			name: "gcp_serverless",
+			args: args{resourceGCPServerless},
+			want: &estimation.EstimationResource{
+				Resource:        &resourceGCPServerless,
+				Power:           decimal.NewFromFloat(0.950098).RoundFloor(10),
+				CarbonEmissions: decimal.NewFromFloat(0.056055782).RoundFloor(10),
+				AverageCPUUsage: decimal.NewFromFloat(avgCPUUse),
+				TotalCount:      decimal.NewFromInt(1),
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, _ := EstimateResource(tt.args.resource)
+			got, _ := EstimateResource(viper.GetViper(), tt.args.resource)
 			EqualsEstimationResource(t, tt.want, got)
 		})
 	}
 }
 
+func TestEstimateResourceUtilization(t *testing.T) {
+	got, _ := EstimateResource(viper.GetViper(), resourceGCPComputeBusy)
+	EqualsEstimationResource(t, &estimation.EstimationResource{
+		Resource:        &resourceGCPComputeBusy,
+		Power:           decimal.NewFromFloat(10.895184),
+		CarbonEmissions: decimal.NewFromFloat(0.642815856),
+		AverageCPUUsage: decimal.NewFromFloat(0.9),
+		TotalCount:      decimal.NewFromInt(1),
+	}, got)
+	assert.Equal(t, resources.SourceLabel, got.AverageCPUUsageSource)
+
+	got, _ = EstimateResource(viper.GetViper(), resourceGCPComputeBasic)
+	assert.Equal(t, resources.SourceConfig, got.AverageCPUUsageSource)
+}
+
+func TestEstimateResourceUncertainty(t *testing.T) {
+	regionEmissions, err := coefficients.RegionEmission(viper.GetString("data.path"), providers.GCP, "europe-west9")
+	assert.NoError(t, err)
+	lowGridCarbonIntensity := regionEmissions.GridCarbonIntensity.Div(decimal.NewFromInt(1000)).Mul(decimal.NewFromFloat(0.8))
+	highGridCarbonIntensity := regionEmissions.GridCarbonIntensity.Div(decimal.NewFromInt(1000)).Mul(decimal.NewFromFloat(1.2))
+
+	// CPU use 0.5 +/- 0.2, PUE 1.16 +/- 10%
+	lowPower := decimal.NewFromFloat(5.3582256)
+	highPower := decimal.NewFromFloat(10.1727824)
+
+	got, _ := EstimateResource(viper.GetViper(), resourceGCPComputeBasic)
+	assert.Equal(t, lowPower.String(), got.PowerRange.Low.String())
+	assert.Equal(t, highPower.String(), got.PowerRange.High.String())
+	assert.Equal(t, lowPower.Mul(lowGridCarbonIntensity).RoundFloor(10).String(), got.CarbonEmissionsRange.Low.String())
+	assert.Equal(t, highPower.Mul(highGridCarbonIntensity).RoundFloor(10).String(), got.CarbonEmissionsRange.High.String())
+	assert.Equal(t, "1", got.TotalCountRange.Low.String())
+	assert.Equal(t, "1", got.TotalCountRange.High.String())
+	assert.True(t, got.PowerRange.Low.LessThan(got.Power) && got.Power.LessThan(got.PowerRange.High))
+
+	// Autoscaled group, with counts of the autoscaler uncertainty
+	autoscaledGroup := resourceGCPInstanceGroup
+	identification := *resourceGCPInstanceGroup.Identification
+	identification.CountLow = 2
+	identification.CountHigh = 5
+	autoscaledGroup.Identification = &identification
+	report := EstimateResources(viper.GetViper(), map[string]resources.Resource{"group": autoscaledGroup})
+	assert.Equal(t, "3", report.Total.ResourcesCount.String())
+	assert.Equal(t, lowPower.Mul(decimal.NewFromInt(2)).String(), report.Total.PowerRange.Low.String())
+	assert.Equal(t, highPower.Mul(decimal.NewFromInt(5)).String(), report.Total.PowerRange.High.String())
+}
+
+func TestEstimateResourceWater(t *testing.T) {
+	// Google does not publish the WUE of its regions: water is not reported
+	got, _ := EstimateResource(viper.GetViper(), resourceGCPComputeBasic)
+	assert.False(t, got.Water.Valid)
+
+	// AWS region, energy without PUE * WUE of 0.18 L/kWh
+	resourceAWSCompute := resources.ComputeResource{
+		Identification: &resources.ResourceIdentification{
+			Address:           "aws_instance.machine-name-1",
+			Name:              "machine-name-1",
+			ResourceType:      "aws_instance",
+			Provider:          providers.AWS,
+			Region:            "us-east-1",
+			ReplicationFactor: 1,
+			Count:             1,
+		},
+		Specs: resourceGCPComputeBasic.Specs,
+	}
+	got, _ = EstimateResource(viper.GetViper(), resourceAWSCompute)
+	coefs, _ := coefficients.GetEnergyCoefficients(viper.GetString("data.path"))
+	awsPue := coefs.AWS.PueAverage
+	wantWater := got.Power.Div(awsPue).Div(decimal.NewFromInt(1000)).Mul(decimal.NewFromFloat(0.18))
+	assert.True(t, got.Water.Valid)
+	assert.Equal(t, wantWater.RoundFloor(6).String(), got.Water.Decimal.RoundFloor(6).String())
+
+	report := EstimateResources(viper.GetViper(), map[string]resources.Resource{
+		resourceAWSCompute.GetAddress(): resourceAWSCompute,
+	})
+	assert.Equal(t, got.Water.Decimal.String(), report.Total.Water.Decimal.String())
+	assert.Equal(t, "L/h", report.Info.UnitWaterTime)
+
+	// Total is unknown if the water of a resource is unknown
+	report = EstimateResources(viper.GetViper(), map[string]resources.Resource{
+		resourceAWSCompute.GetAddress():      resourceAWSCompute,
+		resourceGCPComputeBasic.GetAddress(): resourceGCPComputeBasic,
+	})
+	assert.False(t, report.Total.Water.Valid)
+
+}
+
+func TestEstimateResourceMarketBased(t *testing.T) {
+	// No carbon free energy known for the region: market-based is not reported
+	got, _ := EstimateResource(viper.GetViper(), resourceGCPComputeBasic)
+	assert.False(t, got.CarbonFreeEnergy.Valid)
+	assert.False(t, got.MarketBasedEmissions.Valid)
+
+	// Renewable coverage set in config
+	viper.Set("market_based.renewable_coverage.gcp", 0.75)
+	defer viper.Set("market_based.renewable_coverage.gcp", nil)
+	got, _ = EstimateResource(viper.GetViper(), resourceGCPComputeBasic)
+	assert.Equal(t, "0.75", got.CarbonFreeEnergy.Decimal.String())
+	assert.Equal(t, got.CarbonEmissions.Div(decimal.NewFromInt(4)).RoundFloor(10).String(), got.MarketBasedEmissions.Decimal.String())
+
+	// AWS and GCP resources in the same report
+	resourceAWSCompute := resources.ComputeResource{
+		Identification: &resources.ResourceIdentification{
+			Address:           "aws_instance.machine-name-1",
+			Name:              "machine-name-1",
+			ResourceType:      "aws_instance",
+			Provider:          providers.AWS,
+			Region:            "us-east-1",
+			ReplicationFactor: 1,
+			Count:             1,
+		},
+		Specs: resourceGCPComputeBasic.Specs,
+	}
+	report := EstimateResources(viper.GetViper(), map[string]resources.Resource{
+		resourceGCPComputeBasic.GetAddress(): resourceGCPComputeBasic,
+		resourceAWSCompute.GetAddress():      resourceAWSCompute,
+	})
+	SortEstimations(&report.Resources)
+	assert.Equal(t, "aws_instance.machine-name-1", report.Resources[0].Resource.GetAddress())
+	assert.False(t, report.Resources[0].MarketBasedEmissions.Valid)
+	assert.True(t, report.Resources[1].MarketBasedEmissions.Valid)
+	assert.False(t, report.Total.MarketBasedEmissions.Valid)
+
+	// Renewable coverage of both providers set in config
+	viper.Set("market_based.renewable_coverage.aws", 1)
+	defer viper.Set("market_based.renewable_coverage.aws", nil)
+	report = EstimateResources(viper.GetViper(), map[string]resources.Resource{
+		resourceGCPComputeBasic.GetAddress(): resourceGCPComputeBasic,
+		resourceAWSCompute.GetAddress():      resourceAWSCompute,
+	})
+	SortEstimations(&report.Resources)
+	assert.Equal(t, "1", report.Resources[0].CarbonFreeEnergy.Decimal.String())
+	assert.True(t, report.Resources[0].CarbonEmissions.IsPositive())
+	assert.True(t, report.Resources[0].MarketBasedEmissions.Decimal.IsZero())
+	assert.Equal(t, report.Resources[1].MarketBasedEmissions.Decimal.String(), report.Total.MarketBasedEmissions.Decimal.String())
+}
+
 func TestEstimateResourceKilo(t *testing.T) {
 	avgCPUUse := viper.GetFloat64("provider.gcp.avg_cpu_use")
 	viper.Set("unit.carbon", "kg")
@@ -169,7 +367,7 @@ func TestEstimateResourceKilo(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, _ := EstimateResource(tt.args.resource)
+			got, _ := EstimateResource(viper.GetViper(), tt.args.resource)
 			EqualsEstimationResource(t, tt.want, got)
 		})
 	}
@@ -192,7 +390,7 @@ func TestEstimateResourceUnsupported(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			_, err := EstimateResource(tt.args.resource)
+			_, err := EstimateResource(viper.GetViper(), tt.args.resource)
 			//assert.Equal(t, got.Power, tt.want.Power)
 			if !reflect.DeepEqual(err, tt.want) {
 				t.Errorf("EstimateResource() = %v, want %v", err, tt.want)
@@ -207,6 +405,8 @@ func EqualsEstimationResource(t *testing.T, expected *estimation.EstimationResou
 	assert.Equal(t, expected.CarbonEmissions.String(), actual.CarbonEmissions.String())
 	assert.Equal(t, expected.AverageCPUUsage.String(), actual.AverageCPUUsage.String())
 	assert.Equal(t, expected.TotalCount.String(), actual.TotalCount.String())
+	assert.Equal(t, expected.NetworkPower.String(), actual.NetworkPower.String())
+	assert.Equal(t, expected.NetworkCarbonEmissions.String(), actual.NetworkCarbonEmissions.String())
 
 }
 
@@ -215,6 +415,8 @@ func EqualsTotal(t *testing.T, expected *estimation.EstimationTotal, actual *est
 	assert.Equal(t, expected.Power.String(), actual.Power.String())
 	assert.Equal(t, expected.CarbonEmissions.String(), actual.CarbonEmissions.String())
 	assert.Equal(t, expected.ResourcesCount.String(), actual.ResourcesCount.String())
+	assert.Equal(t, expected.NetworkPower.String(), actual.NetworkPower.String())
+	assert.Equal(t, expected.NetworkCarbonEmissions.String(), actual.NetworkCarbonEmissions.String())
 }
 
 func TestEstimateResources(t *testing.T) {
@@ -281,7 +483,7 @@ func TestEstimateResources(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := EstimateResources(tt.args.resources)
+			got := EstimateResources(viper.GetViper(), tt.args.resources)
 			assert.Equal(t, got.Info.UnitCarbonEmissionsTime, tt.want.Info.UnitCarbonEmissionsTime)
 			assert.Equal(t, got.Info.UnitTime, tt.want.Info.UnitTime)
 			assert.Equal(t, got.Info.UnitWattTime, tt.want.Info.UnitWattTime)
@@ -295,3 +497,152 @@ func TestEstimateResources(t *testing.T) {
 		})
 	}
 }
+
+func TestEstimateResourcesPartial(t *testing.T) {
+	viper.Set("unit.carbon", "g")
+	viper.Set("unit.time", "h")
+	resourceUnknownRegion := resourceGCPComputeBasic
+	resourceUnknownRegion.Identification = &resources.ResourceIdentification{
+		Address:           "google_compute_instance.machine-mars",
+		Name:              "machine-mars",
+		ResourceType:      "type-1",
+		Provider:          providers.GCP,
+		Region:            "mars-east1",
+		ReplicationFactor: 1,
+		Count:             1,
+	}
+	resourceFailed := resources.FailedResource{
+		Identification: &resources.ResourceIdentification{
+			Address:  "google_compute_instance.machine-broken",
+			Name:     "machine-broken",
+			Provider: providers.GCP,
+		},
+		Err: &providers.UnknownInstanceTypeError{Provider: providers.GCP, InstanceType: "e9-unknown-2"},
+	}
+
+	_, err := EstimateResource(viper.GetViper(), resourceUnknownRegion)
+	var unknownRegionError *providers.UnknownRegionError
+	assert.ErrorAs(t, err, &unknownRegionError)
+	assert.Equal(t, "mars-east1", unknownRegionError.Region)
+
+	got := EstimateResources(viper.GetViper(), map[string]resources.Resource{
+		"type-1.machine-name-1": resourceGCPComputeBasic,
+		"type-1.machine-mars":   resourceUnknownRegion,
+		"type-1.machine-broken": resourceFailed,
+	})
+	assert.Len(t, got.Resources, 3)
+	errorsByAddress := map[string]string{}
+	for _, resource := range got.Resources {
+		errorsByAddress[resource.Resource.GetIdentification().Address] = resource.Error
+	}
+	assert.Equal(t, "", errorsByAddress["google_compute_instance.machine-name-1"])
+	assert.Contains(t, errorsByAddress["google_compute_instance.machine-mars"], "mars-east1")
+	assert.Contains(t, errorsByAddress["google_compute_instance.machine-broken"], "e9-unknown-2")
+
+	// Only the estimated resource is counted in the totals
+	assert.Equal(t, decimal.NewFromFloat(7.600784).Round(10).String(), got.Total.Power.Round(10).String())
+	assert.Equal(t, "1", got.Total.ResourcesCount.String())
+}
+
+func TestEstimateResourcesDataQuality(t *testing.T) {
+	viper.Set("unit.carbon", "g")
+	viper.Set("unit.time", "h")
+	resourceDefaults := resourceGCPComputeBasic
+	resourceDefaults.Identification = &resources.ResourceIdentification{
+		Address:           "google_compute_instance.machine-defaults",
+		Name:              "machine-defaults",
+		ResourceType:      "type-1",
+		Provider:          providers.GCP,
+		Region:            "europe-west9",
+		ReplicationFactor: 1,
+		Count:             1,
+	}
+	resourceDefaults.Specs = &resources.ComputeResourceSpecs{
+		VCPUs:      2,
+		MemoryMb:   4096,
+		HddStorage: decimal.NewFromInt(10),
+		Provenance: map[string]resources.Provenance{
+			"vCPUs":              {Source: resources.ProvenanceDataFile, Reference: "gcp_instances.json (.vcpus)", Key: "e2-medium"},
+			"replication_factor": {Source: resources.ProvenanceDefault},
+			"storage[0].size":    {Source: resources.ProvenanceDefault},
+		},
+	}
+	resourceUnknownType := resourceGCPComputeBasic
+	resourceUnknownType.Identification = &resources.ResourceIdentification{
+		Address:           "google_compute_instance.machine-unknown",
+		Name:              "machine-unknown",
+		ResourceType:      "type-1",
+		Provider:          providers.GCP,
+		Region:            "europe-west9",
+		ReplicationFactor: 1,
+		Count:             1,
+	}
+	resourceUnknownType.Specs = &resources.ComputeResourceSpecs{
+		Provenance: map[string]resources.Provenance{
+			"vCPUs":           {Source: resources.ProvenanceNotFound, Reference: "gcp_instances.json (.vcpus)", Key: "e9-unknown-2"},
+			"storage[0].size": {Source: resources.ProvenanceDefault},
+		},
+	}
+
+	got := EstimateResources(viper.GetViper(), map[string]resources.Resource{
+		"type-1.machine-name-1":   resourceGCPComputeBasic,
+		"type-1.machine-defaults": resourceDefaults,
+		"type-1.machine-unknown":  resourceUnknownType,
+	})
+	SortEstimations(&got.Resources)
+	assert.Len(t, got.Resources, 3)
+
+	assert.Equal(t, resources.DataQualityMedium, got.Resources[0].DataQuality)
+	assert.Equal(t, []string{"storage[0].size: default value"}, got.Resources[0].Warnings)
+	assert.Equal(t, resources.DataQualityHigh, got.Resources[1].DataQuality)
+	assert.Empty(t, got.Resources[1].Warnings)
+	assert.Equal(t, resources.DataQualityLow, got.Resources[2].DataQuality)
+	assert.Equal(t, []string{
+		"storage[0].size: default value",
+		"vCPUs: 'e9-unknown-2' not found in gcp_instances.json (.vcpus)",
+	}, got.Resources[2].Warnings)
+	assert.Equal(t, 2, got.Total.ResourcesWithDefaults)
+}
+
+func TestEstimateResourcesNetworking(t *testing.T) {
+	avgCPUUse := viper.GetFloat64("provider.gcp.avg_cpu_use")
+	viper.Set("unit.carbon", "g")
+	viper.Set("unit.time", "h")
+
+	expectedResources := []estimation.EstimationResource{
+		{
+			Resource:        &resourceGCPComputeBasic,
+			Power:           decimal.NewFromFloat(7.600784).Round(10),
+			CarbonEmissions: decimal.NewFromFloat(0.448446256).Round(10),
+			AverageCPUUsage: decimal.NewFromFloat(avgCPUUse),
+			TotalCount:      decimal.NewFromInt(1),
+		},
+		{
+			Resource:               &resourceGCPLoadBalancer,
+			Power:                  decimal.Zero,
+			CarbonEmissions:        decimal.Zero,
+			AverageCPUUsage:        decimal.NewFromFloat(avgCPUUse),
+			TotalCount:             decimal.NewFromInt(1),
+			NetworkPower:           decimal.NewFromFloat(1.856),
+			NetworkCarbonEmissions: decimal.NewFromFloat(0.109504),
+		},
+	}
+	SortEstimations(&expectedResources)
+
+	got := EstimateResources(viper.GetViper(), map[string]resources.Resource{
+		"type-1.machine-name-1": resourceGCPComputeBasic,
+		"type-1.lb-1":           resourceGCPLoadBalancer,
+	})
+	SortEstimations(&got.Resources)
+	for i, gotResource := range got.Resources {
+		wantResource := expectedResources[i]
+		EqualsEstimationResource(t, &wantResource, &gotResource)
+	}
+	EqualsTotal(t, &estimation.EstimationTotal{
+		Power:                  decimal.NewFromFloat(9.456784),
+		CarbonEmissions:        decimal.NewFromFloat(0.557950256),
+		ResourcesCount:         decimal.NewFromInt(2),
+		NetworkPower:           decimal.NewFromFloat(1.856),
+		NetworkCarbonEmissions: decimal.NewFromFloat(0.109504),
+	}, &got.Total)
+}
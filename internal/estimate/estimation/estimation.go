@@ -23,6 +23,42 @@ type EstimationResource struct {
 	CarbonEmissions decimal.Decimal `json:"CarbonEmissionsPerInstance"`
 	AverageCPUUsage decimal.Decimal
 	TotalCount      decimal.Decimal `json:"TotalCount"` // Count * ReplicationFactor
+	// Where the average usages come from: config, tag, label or assumptions file
+	AverageCPUUsageSource string
+	// CPU platform assumed for the CPU power, empty if the flat coefficients of the provider are used
+	CPUPlatform           string `json:",omitempty"`
+	AverageGPUUsage       decimal.Decimal
+	AverageGPUUsageSource string
+	// Networking of the whole resource, not per instance
+	NetworkPower           decimal.Decimal
+	NetworkCarbonEmissions decimal.Decimal
+	// Market-based emissions, electricity covered by carbon free energy excluded, null if the carbon free energy is unknown
+	MarketBasedEmissions        decimal.NullDecimal `json:"MarketBasedEmissionsPerInstance"`
+	NetworkMarketBasedEmissions decimal.NullDecimal
+	// Share of the electricity covered by carbon free energy, between 0 and 1, null if unknown
+	CarbonFreeEnergy decimal.NullDecimal
+	// Embodied emissions of the share of the host used by an instance
+	EmbodiedEmissions decimal.Decimal `json:"EmbodiedEmissionsPerInstance"`
+	// Water used on site by an instance, in Litres, null if the WUE of the region is unknown
+	Water decimal.NullDecimal `json:"WaterPerInstance"`
+	// Low and high estimations, when assumptions vary within their uncertainty
+	PowerRange                  EstimationRange `json:"PowerPerInstanceRange"`
+	CarbonEmissionsRange        EstimationRange `json:"CarbonEmissionsPerInstanceRange"`
+	TotalCountRange             EstimationRange
+	NetworkPowerRange           EstimationRange
+	NetworkCarbonEmissionsRange EstimationRange
+	// Data quality of the specs: high, medium if some are defaults of the mappings, low if some were not found
+	DataQuality string `json:",omitempty"`
+	// Specs that are default values or were not found, such as the size of a disk or an unknown machine type
+	Warnings []string `json:",omitempty"`
+	// Reason the resource could not be estimated, its estimations being zero
+	Error string `json:",omitempty"`
+}
+
+// EstimationRange is the struct that contains the low and high bounds of an estimation
+type EstimationRange struct {
+	Low  decimal.Decimal
+	High decimal.Decimal
 }
 
 // EstimationTotal is the struct that contains the total estimation
@@ -30,6 +66,21 @@ type EstimationTotal struct {
 	Power           decimal.Decimal
 	CarbonEmissions decimal.Decimal
 	ResourcesCount  decimal.Decimal
+	// Networking part of Power and CarbonEmissions
+	NetworkPower           decimal.Decimal
+	NetworkCarbonEmissions decimal.Decimal
+	// Market-based emissions, networking included, null if the carbon free energy of a resource is unknown
+	MarketBasedEmissions        decimal.NullDecimal
+	NetworkMarketBasedEmissions decimal.NullDecimal
+	// Embodied emissions, not part of CarbonEmissions
+	EmbodiedEmissions decimal.Decimal
+	// Water used on site, in Litres, null if the WUE of the region of a resource is unknown
+	Water decimal.NullDecimal
+	// Low and high estimations, when assumptions vary within their uncertainty
+	PowerRange           EstimationRange
+	CarbonEmissionsRange EstimationRange
+	// Number of resources estimated with specs that are default values or were not found
+	ResourcesWithDefaults int
 }
 
 // EstimationInfo is the struct that contains the info of the estimation
@@ -37,8 +88,18 @@ type EstimationInfo struct {
 	UnitTime                string
 	UnitWattTime            string
 	UnitCarbonEmissionsTime string
+	UnitWaterTime           string
 	DateTime                time.Time
 	InfoByProvider          map[providers.Provider]InfoByProvider
+	Uncertainty             Uncertainty
+}
+
+// Uncertainty is the struct that contains the variations of the assumptions for the low and high estimations
+type Uncertainty struct {
+	Utilization              float64 // added to or removed from the average CPU and GPU utilizations
+	AvgAutoscalerSizePercent float64 // added to or removed from the average autoscaler size
+	PUE                      float64 // relative variation of the PUE
+	GridCarbonIntensity      float64 // relative variation of the grid carbon intensity
 }
 
 // InfoByProvider is the struct that contains the info of the estimation by provider
@@ -46,3 +107,59 @@ type InfoByProvider struct {
 	AverageCPUUsage float64
 	AverageGPUUsage float64
 }
+
+// SCIReport is the struct that contains the Software Carbon Intensity (SCI) of the estimation: ((E * I) + M) per R
+type SCIReport struct {
+	FunctionalUnit      string          // R, the functional unit, such as request or user
+	FunctionalUnitCount decimal.Decimal // number of functional units per unit of time
+	UnitSCI             string
+	Resources           []SCIResource
+	Total               SCIResource
+}
+
+// SCIResource is the struct that contains the SCI of a resource, all instances included
+type SCIResource struct {
+	Address              string
+	OperationalEmissions decimal.Decimal // E * I
+	EmbodiedEmissions    decimal.Decimal // M
+	SCI                  decimal.Decimal
+	Water                decimal.NullDecimal // Water per functional unit, in Litres, null if unknown
+}
+
+// ComparisonReport is the struct that contains the estimations of instances compared side by side, from the lowest emissions
+type ComparisonReport struct {
+	UnitPower                string
+	UnitCarbonEmissionsHour  string
+	UnitCarbonEmissionsMonth string
+	UnitCarbonEmissionsYear  string
+	DateTime                 time.Time
+	Candidates               []ComparisonCandidate
+}
+
+// ComparisonCandidate is the struct that contains the estimation of one instance of a comparison
+type ComparisonCandidate struct {
+	Name                    string
+	Provider                providers.Provider
+	Region                  string
+	VCPUs                   int32
+	MemoryMb                int32
+	CPUPlatform             string `json:",omitempty"`
+	Power                   decimal.Decimal
+	CarbonEmissionsPerHour  decimal.Decimal
+	CarbonEmissionsPerMonth decimal.Decimal
+	CarbonEmissionsPerYear  decimal.Decimal
+	// Hourly emissions divided by the number of vCPUs and by the memory in GB
+	CarbonEmissionsPerVCPU     decimal.Decimal
+	CarbonEmissionsPerMemoryGb decimal.Decimal
+	// Reason the instance could not be estimated, its estimations being zero
+	Error string `json:",omitempty"`
+}
+
+// FormulaStep is a step of the estimation of a resource, with the coefficients used and where they come from
+type FormulaStep struct {
+	Name    string
+	Formula string // with the values used, such as "2 vCPUs * (0.71 + 0.5 * (4.26 - 0.71)) Wh"
+	Value   decimal.Decimal
+	Unit    string
+	Sources []string `json:",omitempty"` // data files and config keys of the coefficients
+}
@@ -0,0 +1,60 @@
+package estimate
+
+import (
+	"fmt"
+
+	"github.com/carboniferio/carbonifer/internal/estimate/estimation"
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+	"github.com/spf13/viper"
+)
+
+// EstimateSCI computes the Software Carbon Intensity (SCI) of an estimation report, ((E * I) + M) per R,
+// for the functional unit R set in config `sci`
+func EstimateSCI(cfg *viper.Viper, report estimation.EstimationReport) (*estimation.SCIReport, error) {
+	functionalUnit := cfg.GetString("sci.functional_unit")
+	if functionalUnit == "" {
+		return nil, errors.New("SCI needs a functional unit, set by config `sci.functional_unit`")
+	}
+	functionalUnitCount := decimal.NewFromFloat(cfg.GetFloat64("sci.functional_unit_count"))
+	if !functionalUnitCount.IsPositive() {
+		return nil, errors.Errorf("SCI needs a positive number of %v per %v, set by config `sci.functional_unit_count`", functionalUnit, report.Info.UnitTime)
+	}
+
+	sciReport := estimation.SCIReport{
+		FunctionalUnit:      functionalUnit,
+		FunctionalUnitCount: functionalUnitCount,
+		UnitSCI:             fmt.Sprintf("%sCO2eq/%s", cfg.Get("unit.carbon"), functionalUnit),
+		Resources:           []estimation.SCIResource{},
+	}
+
+	estimations := report.Resources
+	SortEstimations(&estimations)
+	for _, resource := range estimations {
+		operationalEmissions := resource.CarbonEmissions.Mul(resource.TotalCount).Add(resource.NetworkCarbonEmissions)
+		embodiedEmissions := resource.EmbodiedEmissions.Mul(resource.TotalCount)
+		sciReport.Resources = append(sciReport.Resources, estimation.SCIResource{
+			Address:              resource.Resource.GetAddress(),
+			OperationalEmissions: operationalEmissions,
+			EmbodiedEmissions:    embodiedEmissions,
+			SCI:                  operationalEmissions.Add(embodiedEmissions).Div(functionalUnitCount).RoundFloor(10),
+			Water:                perFunctionalUnit(mulNullDecimal(resource.Water, resource.TotalCount), functionalUnitCount),
+		})
+	}
+	sciReport.Total = estimation.SCIResource{
+		Address:              "Total",
+		OperationalEmissions: report.Total.CarbonEmissions,
+		EmbodiedEmissions:    report.Total.EmbodiedEmissions,
+		SCI:                  report.Total.CarbonEmissions.Add(report.Total.EmbodiedEmissions).Div(functionalUnitCount).RoundFloor(10),
+		Water:                perFunctionalUnit(report.Total.Water, functionalUnitCount),
+	}
+	return &sciReport, nil
+}
+
+// perFunctionalUnit divides a value by the number of functional units, null if the value is null
+func perFunctionalUnit(value decimal.NullDecimal, functionalUnitCount decimal.Decimal) decimal.NullDecimal {
+	if !value.Valid {
+		return value
+	}
+	return decimal.NewNullDecimal(value.Decimal.Div(functionalUnitCount).RoundFloor(10))
+}
@@ -0,0 +1,53 @@
+package estimate
+
+import (
+	"testing"
+
+	"github.com/carboniferio/carbonifer/internal/resources"
+	"github.com/shopspring/decimal"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEstimateEmbodiedEmissions(t *testing.T) {
+	// 876 kgCO2eq per host of 50 vCPUs, over 4 years
+	got, _ := EstimateResource(viper.GetViper(), resourceGCPComputeBasic)
+	assert.Equal(t, decimal.NewFromInt(1).String(), got.EmbodiedEmissions.String())
+
+	// Networking only
+	got, _ = EstimateResource(viper.GetViper(), resourceGCPLoadBalancer)
+	assert.True(t, got.EmbodiedEmissions.IsZero())
+}
+
+func TestEstimateSCI(t *testing.T) {
+	viper.Set("sci.functional_unit", "request")
+	viper.Set("sci.functional_unit_count", 100)
+	defer viper.Set("sci.functional_unit_count", 0)
+
+	report := EstimateResources(viper.GetViper(), map[string]resources.Resource{
+		resourceGCPComputeBasic.GetAddress():  resourceGCPComputeBasic,
+		resourceGCPInstanceGroup.GetAddress(): resourceGCPInstanceGroup,
+	})
+	assert.Equal(t, decimal.NewFromInt(4).String(), report.Total.EmbodiedEmissions.String())
+
+	got, err := EstimateSCI(viper.GetViper(), report)
+	assert.NoError(t, err)
+	assert.Equal(t, "gCO2eq/request", got.UnitSCI)
+	assert.Equal(t, 2, len(got.Resources))
+
+	group := got.Resources[1]
+	assert.Equal(t, resourceGCPInstanceGroup.GetAddress(), group.Address)
+	assert.Equal(t, decimal.NewFromInt(3).String(), group.EmbodiedEmissions.String())
+	assert.Equal(t, group.OperationalEmissions.Add(group.EmbodiedEmissions).Div(decimal.NewFromInt(100)).RoundFloor(10).String(), group.SCI.String())
+
+	wantTotal := report.Total.CarbonEmissions.Add(decimal.NewFromInt(4)).Div(decimal.NewFromInt(100)).RoundFloor(10)
+	assert.Equal(t, wantTotal.String(), got.Total.SCI.String())
+}
+
+func TestEstimateSCI_NoFunctionalUnitCount(t *testing.T) {
+	report := EstimateResources(viper.GetViper(), map[string]resources.Resource{
+		resourceGCPComputeBasic.GetAddress(): resourceGCPComputeBasic,
+	})
+	_, err := EstimateSCI(viper.GetViper(), report)
+	assert.ErrorContains(t, err, "sci.functional_unit_count")
+}
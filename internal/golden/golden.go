@@ -0,0 +1,367 @@
+package golden
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/carboniferio/carbonifer/internal/estimate"
+	"github.com/carboniferio/carbonifer/internal/estimate/estimation"
+	"github.com/carboniferio/carbonifer/internal/plan"
+	"github.com/carboniferio/carbonifer/internal/resources"
+	"github.com/carboniferio/carbonifer/internal/terraform"
+	"github.com/pkg/errors"
+	"github.com/polkeli/yaml/v3"
+	"github.com/shopspring/decimal"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+	"golang.org/x/exp/maps"
+)
+
+// Statuses of a fixture run against its snapshot
+const (
+	StatusPassed  = "passed"
+	StatusFailed  = "failed"
+	StatusUpdated = "updated"
+)
+
+// snapshotSuffixes are the suffixes of the snapshot of a fixture, such as plan.golden.yaml for plan.json.
+// The first one is used to create snapshots.
+var snapshotSuffixes = []string{".golden.yaml", ".golden.json"}
+
+// Decimal places of the estimations in snapshots, for them not to change with rounding errors
+const snapshotPlaces = 6
+
+// Snapshot is the expected estimation of a terraform plan fixture
+type Snapshot struct {
+	Units       SnapshotUnits               `yaml:"units" json:"units"`
+	Resources   map[string]SnapshotResource `yaml:"resources" json:"resources"`
+	Unsupported []string                    `yaml:"unsupported,omitempty" json:"unsupported,omitempty"`
+	Total       SnapshotTotal               `yaml:"total" json:"total"`
+}
+
+// SnapshotUnits are the units of the estimations of a snapshot
+type SnapshotUnits struct {
+	Power           string `yaml:"power" json:"power"`
+	CarbonEmissions string `yaml:"carbon_emissions" json:"carbon_emissions"`
+}
+
+// SnapshotResource is the expected specs and estimation of a resource, per instance
+type SnapshotResource struct {
+	Type              string         `yaml:"type" json:"type"`
+	Provider          string         `yaml:"provider" json:"provider"`
+	Region            string         `yaml:"region" json:"region"`
+	Count             int64          `yaml:"count" json:"count"`
+	ReplicationFactor int32          `yaml:"replication_factor" json:"replication_factor"`
+	Specs             *SnapshotSpecs `yaml:"specs,omitempty" json:"specs,omitempty"`
+	Power             string         `yaml:"power" json:"power"`
+	CarbonEmissions   string         `yaml:"carbon_emissions" json:"carbon_emissions"`
+	Error             string         `yaml:"error,omitempty" json:"error,omitempty"`
+}
+
+// SnapshotSpecs are the expected specs of a compute resource
+type SnapshotSpecs struct {
+	VCPUs            int32    `yaml:"vcpus" json:"vcpus"`
+	MemoryMb         int32    `yaml:"memory_mb" json:"memory_mb"`
+	HddStorageGb     string   `yaml:"hdd_storage_gb" json:"hdd_storage_gb"`
+	SsdStorageGb     string   `yaml:"ssd_storage_gb" json:"ssd_storage_gb"`
+	ArchiveStorageGb string   `yaml:"archive_storage_gb,omitempty" json:"archive_storage_gb,omitempty"` // object storage in archive classes
+	GPUs             []string `yaml:"gpus,omitempty" json:"gpus,omitempty"`
+	CPUType          string   `yaml:"cpu_type,omitempty" json:"cpu_type,omitempty"`
+}
+
+// SnapshotTotal is the expected total estimation of a snapshot
+type SnapshotTotal struct {
+	Power           string `yaml:"power" json:"power"`
+	CarbonEmissions string `yaml:"carbon_emissions" json:"carbon_emissions"`
+	ResourcesCount  string `yaml:"resources_count" json:"resources_count"`
+}
+
+// Result is the result of a fixture run against its snapshot
+type Result struct {
+	Fixture     string
+	Snapshot    string
+	Status      string
+	Differences []string `json:",omitempty"`
+	Error       string   `json:",omitempty"`
+}
+
+// Run estimates every terraform plan JSON fixture of a directory, and compares the results with their snapshots.
+// If update is true, snapshots are written instead, created if missing.
+func Run(cfg *viper.Viper, dir string, update bool) ([]Result, error) {
+	fixtures, err := Fixtures(dir)
+	if err != nil {
+		return nil, err
+	}
+	results := []Result{}
+	for _, fixture := range fixtures {
+		results = append(results, RunFixture(cfg, fixture, update))
+	}
+	return results, nil
+}
+
+// Fixtures returns the terraform plan JSON files of a directory, snapshots excluded
+func Fixtures(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, errors.Wrap(err, "Cannot read fixtures directory")
+	}
+	fixtures := []string{}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".json") || isSnapshot(name) {
+			continue
+		}
+		fixtures = append(fixtures, filepath.Join(dir, name))
+	}
+	sort.Strings(fixtures)
+	return fixtures, nil
+}
+
+// RunFixture estimates a terraform plan JSON fixture, and compares the result with its snapshot, or writes it if update is true
+func RunFixture(cfg *viper.Viper, fixture string, update bool) Result {
+	result := Result{Fixture: fixture, Snapshot: snapshotPath(fixture)}
+	actual, err := Estimate(cfg, fixture)
+	if err != nil {
+		result.Status = StatusFailed
+		result.Error = err.Error()
+		return result
+	}
+
+	if update {
+		if err := writeSnapshot(result.Snapshot, actual); err != nil {
+			result.Status = StatusFailed
+			result.Error = err.Error()
+			return result
+		}
+		result.Status = StatusUpdated
+		return result
+	}
+
+	expected, err := readSnapshot(result.Snapshot)
+	if err != nil {
+		result.Status = StatusFailed
+		result.Error = err.Error()
+		return result
+	}
+	result.Differences = Compare(*expected, *actual)
+	result.Status = StatusPassed
+	if len(result.Differences) > 0 {
+		result.Status = StatusFailed
+	}
+	return result
+}
+
+// Estimate returns the snapshot of the estimation of a terraform plan JSON fixture
+func Estimate(cfg *viper.Viper, fixture string) (*Snapshot, error) {
+	log.Debugf("Estimating fixture %v", fixture)
+	tfPlan, err := terraform.CarboniferPlan(fixture)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Cannot read terraform plan %v", fixture)
+	}
+	resourceList, err := plan.GetResources(cfg, tfPlan)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Cannot get resources of %v", fixture)
+	}
+	return toSnapshot(estimate.EstimateResources(cfg, resourceList)), nil
+}
+
+func toSnapshot(report estimation.EstimationReport) *Snapshot {
+	snapshot := Snapshot{
+		Units: SnapshotUnits{
+			Power:           report.Info.UnitWattTime,
+			CarbonEmissions: report.Info.UnitCarbonEmissionsTime,
+		},
+		Resources: map[string]SnapshotResource{},
+		Total: SnapshotTotal{
+			Power:           formatDecimal(report.Total.Power),
+			CarbonEmissions: formatDecimal(report.Total.CarbonEmissions),
+			ResourcesCount:  formatDecimal(report.Total.ResourcesCount),
+		},
+	}
+	for _, resource := range report.Resources {
+		identification := resource.Resource.GetIdentification()
+		snapshotResource := SnapshotResource{
+			Type:              identification.ResourceType,
+			Provider:          identification.Provider.String(),
+			Region:            identification.Region,
+			Count:             identification.Count,
+			ReplicationFactor: identification.ReplicationFactor,
+			Power:             formatDecimal(resource.Power),
+			CarbonEmissions:   formatDecimal(resource.CarbonEmissions),
+			Error:             resource.Error,
+		}
+		if computeResource, ok := resource.Resource.(*resources.ComputeResource); ok && computeResource.Specs != nil {
+			specs := computeResource.Specs
+			snapshotResource.Specs = &SnapshotSpecs{
+				VCPUs:        specs.VCPUs,
+				MemoryMb:     specs.MemoryMb,
+				HddStorageGb: formatDecimal(specs.HddStorage),
+				SsdStorageGb: formatDecimal(specs.SsdStorage),
+				GPUs:         specs.GpuTypes,
+				CPUType:      specs.CPUType,
+			}
+			if !specs.ArchiveStorage.IsZero() {
+				snapshotResource.Specs.ArchiveStorageGb = formatDecimal(specs.ArchiveStorage)
+			}
+		}
+		snapshot.Resources[resource.Resource.GetAddress()] = snapshotResource
+	}
+	for _, resource := range report.UnsupportedResources {
+		snapshot.Unsupported = append(snapshot.Unsupported, resource.GetAddress())
+	}
+	sort.Strings(snapshot.Unsupported)
+	return &snapshot
+}
+
+// Compare returns the differences between an expected and an actual snapshot, one per value, empty if they match
+func Compare(expected Snapshot, actual Snapshot) []string {
+	differences := compareValues("units", expected.Units, actual.Units)
+	addresses := maps.Keys(expected.Resources)
+	for address := range actual.Resources {
+		if _, ok := expected.Resources[address]; !ok {
+			addresses = append(addresses, address)
+		}
+	}
+	sort.Strings(addresses)
+	for _, address := range addresses {
+		expectedResource, isExpected := expected.Resources[address]
+		actualResource, isActual := actual.Resources[address]
+		switch {
+		case !isActual:
+			differences = append(differences, fmt.Sprintf("resources.%v: missing", address))
+		case !isExpected:
+			differences = append(differences, fmt.Sprintf("resources.%v: unexpected", address))
+		default:
+			differences = append(differences, compareValues("resources."+address, expectedResource, actualResource)...)
+		}
+	}
+	differences = append(differences, compareValues("unsupported", expected.Unsupported, actual.Unsupported)...)
+	differences = append(differences, compareValues("total", expected.Total, actual.Total)...)
+	return differences
+}
+
+// compareValues returns the differences between the values of two structs, flattened as key paths
+func compareValues(prefix string, expected interface{}, actual interface{}) []string {
+	expectedValues := flatten(prefix, expected)
+	actualValues := flatten(prefix, actual)
+	keys := maps.Keys(expectedValues)
+	for key := range actualValues {
+		if _, ok := expectedValues[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	differences := []string{}
+	for _, key := range keys {
+		expectedValue, isExpected := expectedValues[key]
+		actualValue, isActual := actualValues[key]
+		switch {
+		case !isActual:
+			differences = append(differences, fmt.Sprintf("%v: expected %v, got nothing", key, expectedValue))
+		case !isExpected:
+			differences = append(differences, fmt.Sprintf("%v: expected nothing, got %v", key, actualValue))
+		case expectedValue != actualValue:
+			differences = append(differences, fmt.Sprintf("%v: expected %v, got %v", key, expectedValue, actualValue))
+		}
+	}
+	return differences
+}
+
+// flatten returns the values of a struct by key path, such as specs.gpus[0], as they are written in snapshots
+func flatten(prefix string, value interface{}) map[string]string {
+	values := map[string]string{}
+	var generic interface{}
+	content, err := yaml.Marshal(value)
+	if err == nil {
+		err = yaml.Unmarshal(content, &generic)
+	}
+	if err != nil {
+		values[prefix] = fmt.Sprintf("%v", value)
+		return values
+	}
+	flattenInto(values, prefix, generic)
+	return values
+}
+
+func flattenInto(values map[string]string, prefix string, value interface{}) {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		for key, item := range value {
+			flattenInto(values, prefix+"."+key, item)
+		}
+	case []interface{}:
+		for i, item := range value {
+			flattenInto(values, fmt.Sprintf("%v[%v]", prefix, i), item)
+		}
+	case nil:
+	default:
+		values[prefix] = fmt.Sprintf("%v", value)
+	}
+}
+
+func formatDecimal(value decimal.Decimal) string {
+	return value.Round(snapshotPlaces).String()
+}
+
+func isSnapshot(name string) bool {
+	for _, suffix := range snapshotSuffixes {
+		if strings.HasSuffix(name, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// snapshotPath returns the existing snapshot of a fixture, or else the path of a new YAML one
+func snapshotPath(fixture string) string {
+	base := strings.TrimSuffix(fixture, ".json")
+	for _, suffix := range snapshotSuffixes {
+		if _, err := os.Stat(base + suffix); err == nil {
+			return base + suffix
+		}
+	}
+	return base + snapshotSuffixes[0]
+}
+
+func readSnapshot(snapshotFile string) (*Snapshot, error) {
+	content, err := os.ReadFile(snapshotFile)
+	if os.IsNotExist(err) {
+		return nil, errors.Errorf("No snapshot %v, run with update to create it", snapshotFile)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var snapshot Snapshot
+	if strings.HasSuffix(snapshotFile, ".json") {
+		err = json.Unmarshal(content, &snapshot)
+	} else {
+		err = yaml.Unmarshal(content, &snapshot)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "Invalid snapshot %v", snapshotFile)
+	}
+	return &snapshot, nil
+}
+
+func writeSnapshot(snapshotFile string, snapshot *Snapshot) error {
+	var content []byte
+	var err error
+	if strings.HasSuffix(snapshotFile, ".json") {
+		content, err = json.MarshalIndent(snapshot, "", "  ")
+	} else {
+		var buffer bytes.Buffer
+		encoder := yaml.NewEncoder(&buffer)
+		encoder.SetIndent(2)
+		err = encoder.Encode(snapshot)
+		content = buffer.Bytes()
+	}
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(snapshotFile, content, 0o644)
+}
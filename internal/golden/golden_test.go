@@ -0,0 +1,100 @@
+package golden
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	_ "github.com/carboniferio/carbonifer/internal/testutils"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+var update = flag.Bool("update", false, "update the snapshots of the fixtures")
+
+// TestFixtures checks the estimations of the terraform plan fixtures against their snapshots.
+// Run `go test ./internal/golden -update` to write the snapshots after a change of the mappings.
+func TestFixtures(t *testing.T) {
+	results, err := Run(viper.GetViper(), "test/terraform/planJson", *update)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, results)
+	for _, result := range results {
+		assert.Empty(t, result.Error, result.Fixture)
+		assert.Empty(t, result.Differences, result.Fixture)
+		assert.NotEqual(t, StatusFailed, result.Status, result.Fixture)
+
+		// Resources failing in a snapshot are only the ones the fixture is about, not missing test data
+		snapshot, err := readSnapshot(result.Snapshot)
+		if !assert.NoError(t, err, result.Fixture) {
+			continue
+		}
+		failed := []string{}
+		for address, resource := range snapshot.Resources {
+			if resource.Error != "" {
+				failed = append(failed, address)
+			}
+		}
+		assert.ElementsMatch(t, expectedErrors[filepath.Base(result.Fixture)], failed, result.Fixture)
+	}
+}
+
+// expectedErrors are the resources of the fixtures that cannot be estimated on purpose
+var expectedErrors = map[string][]string{
+	"partial.json":                {"google_compute_disk.broken"},
+	"unknown_instance_types.json": {"aws_instance.batch", "aws_instance.web", "google_compute_instance.dev"},
+}
+
+func TestRunFixture(t *testing.T) {
+	dir := t.TempDir()
+	fixture := filepath.Join(dir, "plan.json")
+	content, err := os.ReadFile("test/terraform/planJson/partial.json")
+	assert.NoError(t, err)
+	assert.NoError(t, os.WriteFile(fixture, content, 0o644))
+
+	result := RunFixture(viper.GetViper(), fixture, false)
+	assert.Equal(t, StatusFailed, result.Status)
+	assert.Contains(t, result.Error, "No snapshot")
+
+	result = RunFixture(viper.GetViper(), fixture, true)
+	assert.Equal(t, StatusUpdated, result.Status)
+	assert.Equal(t, filepath.Join(dir, "plan.golden.yaml"), result.Snapshot)
+
+	fixtures, err := Fixtures(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{fixture}, fixtures)
+
+	result = RunFixture(viper.GetViper(), fixture, false)
+	assert.Equal(t, StatusPassed, result.Status)
+	assert.Empty(t, result.Differences)
+}
+
+func TestCompare(t *testing.T) {
+	expected := Snapshot{
+		Units: SnapshotUnits{Power: "W", CarbonEmissions: "gCO2eq/h"},
+		Resources: map[string]SnapshotResource{
+			"google_compute_instance.first": {Type: "google_compute_instance", Power: "10", Specs: &SnapshotSpecs{VCPUs: 2, GPUs: []string{"nvidia-tesla-t4"}}},
+			"google_compute_instance.gone":  {Type: "google_compute_instance"},
+		},
+		Total: SnapshotTotal{Power: "10"},
+	}
+	actual := Snapshot{
+		Units: SnapshotUnits{Power: "W", CarbonEmissions: "gCO2eq/h"},
+		Resources: map[string]SnapshotResource{
+			"google_compute_instance.first": {Type: "google_compute_instance", Power: "12", Specs: &SnapshotSpecs{VCPUs: 4}},
+			"google_compute_instance.new":   {Type: "google_compute_instance"},
+		},
+		Unsupported: []string{"google_compute_network.vpc"},
+		Total:       SnapshotTotal{Power: "10"},
+	}
+
+	assert.Empty(t, Compare(expected, expected))
+	assert.Equal(t, []string{
+		"resources.google_compute_instance.first.power: expected 10, got 12",
+		"resources.google_compute_instance.first.specs.gpus[0]: expected nvidia-tesla-t4, got nothing",
+		"resources.google_compute_instance.first.specs.vcpus: expected 2, got 4",
+		"resources.google_compute_instance.gone: missing",
+		"resources.google_compute_instance.new: unexpected",
+		"unsupported[0]: expected nothing, got google_compute_network.vpc",
+	}, Compare(expected, actual))
+}
@@ -0,0 +1,102 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/carboniferio/carbonifer/internal/estimate/estimation"
+	"github.com/olekukonko/tablewriter"
+	log "github.com/sirupsen/logrus"
+)
+
+// GenerateComparisonText generates a text table from a comparison report, in the order of its candidates
+func GenerateComparisonText(report estimation.ComparisonReport) string {
+	log.Debug("Generating text comparison")
+	tableString := &strings.Builder{}
+	tableString.WriteString("\n  Comparison of CO2 emissions per instance, from the lowest: \n\n")
+
+	table := tablewriter.NewWriter(tableString)
+	table.SetHeader([]string{
+		"instance",
+		"region",
+		"vCPUs",
+		"memory",
+		"power",
+		"emissions per hour",
+		"per month",
+		"per year",
+		"per vCPU",
+		"per GB of memory",
+	})
+
+	for _, candidate := range report.Candidates {
+		if candidate.Error != "" {
+			table.Append([]string{
+				candidate.Name,
+				candidate.Region,
+				fmt.Sprintf("%v", candidate.VCPUs),
+				FormatMemory(candidate.MemoryMb),
+				"",
+				"error",
+				"",
+				"",
+				"",
+				"",
+			})
+			continue
+		}
+		table.Append([]string{
+			candidate.Name,
+			candidate.Region,
+			fmt.Sprintf("%v", candidate.VCPUs),
+			FormatMemory(candidate.MemoryMb),
+			fmt.Sprintf(" %v %v", candidate.Power.StringFixed(4), report.UnitPower),
+			fmt.Sprintf(" %v %v", candidate.CarbonEmissionsPerHour.StringFixed(4), report.UnitCarbonEmissionsHour),
+			fmt.Sprintf(" %v %v", candidate.CarbonEmissionsPerMonth.StringFixed(4), report.UnitCarbonEmissionsMonth),
+			fmt.Sprintf(" %v %v", candidate.CarbonEmissionsPerYear.StringFixed(4), report.UnitCarbonEmissionsYear),
+			fmt.Sprintf(" %v %v", candidate.CarbonEmissionsPerVCPU.StringFixed(4), report.UnitCarbonEmissionsHour),
+			fmt.Sprintf(" %v %v", candidate.CarbonEmissionsPerMemoryGb.StringFixed(4), report.UnitCarbonEmissionsHour),
+		})
+	}
+
+	// Format
+	table.SetAutoFormatHeaders(false)
+	table.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetBorder(true)
+	table.SetColumnSeparator(" ")
+	table.SetCenterSeparator(" ")
+
+	table.Render()
+
+	errors := []string{}
+	for _, candidate := range report.Candidates {
+		if candidate.Error != "" {
+			errors = append(errors, fmt.Sprintf("  %v in %v: %v\n", candidate.Name, candidate.Region, candidate.Error))
+		}
+	}
+	if len(errors) > 0 {
+		tableString.WriteString("\n  Instances not estimated: \n\n")
+		for _, err := range errors {
+			tableString.WriteString(err)
+		}
+	}
+	return tableString.String()
+}
+
+// GenerateComparisonJSON generates a JSON report from a comparison report
+func GenerateComparisonJSON(report estimation.ComparisonReport) string {
+	log.Debug("Generating JSON comparison")
+
+	reportTextBytes, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		log.Fatal(err)
+	}
+	return string(reportTextBytes)
+}
+
+// FormatMemory formats a memory size in MB as GB
+func FormatMemory(memoryMb int32) string {
+	return fmt.Sprintf("%v GB", float64(memoryMb)/1024)
+}
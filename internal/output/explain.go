@@ -0,0 +1,125 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/carboniferio/carbonifer/internal/estimate/estimation"
+	"github.com/carboniferio/carbonifer/internal/plan"
+	"github.com/carboniferio/carbonifer/internal/resources"
+	"github.com/olekukonko/tablewriter"
+	log "github.com/sirupsen/logrus"
+)
+
+// Explanation is how a resource was estimated, from the terraform plan to its carbon emissions
+type Explanation struct {
+	Plan       *plan.Explanation
+	Steps      []estimation.FormulaStep
+	Estimation *estimation.EstimationResource
+	Info       estimation.EstimationInfo
+}
+
+// GenerateExplanationText generates a text report of how a resource was estimated
+func GenerateExplanationText(explanation Explanation) string {
+	log.Debug("Generating text explanation")
+	tableString := &strings.Builder{}
+	tableString.WriteString(fmt.Sprintf("\n  Explanation of %v \n\n", explanation.Plan.Address))
+	tableString.WriteString(fmt.Sprintf("  Mapping: %v, selected by '%v' \n\n", explanation.Plan.MappingType, explanation.Plan.MappingPath))
+
+	tableString.WriteString("  Properties read from the plan: \n\n")
+	table := newExplanationTable(tableString, []string{"property", "source", "path", "raw value", "reference", "value", "unit"})
+	for _, property := range explanation.Plan.Properties {
+		source := property.Source
+		if source == "" {
+			source = resources.ProvenanceNotFound
+		}
+		table.Append([]string{
+			property.Property,
+			source,
+			property.Path,
+			formatValue(property.RawValue),
+			property.Reference,
+			formatValue(property.Value),
+			property.Unit,
+		})
+	}
+	table.Render()
+
+	if computeResource, ok := explanation.Plan.Resource.(resources.ComputeResource); ok {
+		tableString.WriteString("\n  Resource: \n\n")
+		identification := computeResource.Identification
+		specs := computeResource.Specs
+		tableString.WriteString(fmt.Sprintf("  region: %v, count: %v, replication factor: %v\n", identification.Region, identification.Count, identification.ReplicationFactor))
+		vCPUs := fmt.Sprint(specs.VCPUs)
+		if specs.VCPUs == 0 && !specs.FractionalVCPUs.IsZero() {
+			vCPUs = specs.FractionalVCPUs.String()
+		}
+		tableString.WriteString(fmt.Sprintf("  vCPUs: %v, memory: %v MB, SSD: %v GB, HDD: %v GB\n", vCPUs, specs.MemoryMb, specs.SsdStorage, specs.HddStorage))
+		if !specs.ArchiveStorage.IsZero() {
+			tableString.WriteString(fmt.Sprintf("  archive: %v GB\n", specs.ArchiveStorage))
+		}
+		if len(specs.GpuTypes) > 0 {
+			tableString.WriteString(fmt.Sprintf("  GPUs: %v\n", strings.Join(specs.GpuTypes, ", ")))
+		}
+	}
+
+	if explanation.Estimation != nil && explanation.Estimation.Error != "" {
+		tableString.WriteString(fmt.Sprintf("\n  Not estimated: %v\n", explanation.Estimation.Error))
+		return tableString.String()
+	}
+
+	if len(explanation.Steps) > 0 {
+		tableString.WriteString("\n  Estimation per instance and per hour: \n\n")
+		table = newExplanationTable(tableString, []string{"step", "formula", "value", "sources"})
+		for _, step := range explanation.Steps {
+			table.Append([]string{
+				step.Name,
+				step.Formula,
+				fmt.Sprintf("%v %v", step.Value.StringFixed(4), step.Unit),
+				strings.Join(step.Sources, ", "),
+			})
+		}
+		table.Render()
+	}
+
+	if explanation.Estimation != nil {
+		tableString.WriteString("\n  Reported per instance: \n\n")
+		tableString.WriteString(fmt.Sprintf("  power: %v %v\n", explanation.Estimation.Power.StringFixed(4), explanation.Info.UnitWattTime))
+		tableString.WriteString(fmt.Sprintf("  emissions: %v %v\n", explanation.Estimation.CarbonEmissions.StringFixed(4), explanation.Info.UnitCarbonEmissionsTime))
+		tableString.WriteString(fmt.Sprintf("  instances: %v\n", explanation.Estimation.TotalCount))
+	}
+	return tableString.String()
+}
+
+// GenerateExplanationJSON generates a JSON report of how a resource was estimated
+func GenerateExplanationJSON(explanation Explanation) string {
+	log.Debug("Generating JSON explanation")
+
+	explanationBytes, err := json.MarshalIndent(explanation, "", "  ")
+	if err != nil {
+		log.Fatal(err)
+	}
+	return string(explanationBytes)
+}
+
+func newExplanationTable(tableString *strings.Builder, header []string) *tablewriter.Table {
+	table := tablewriter.NewWriter(tableString)
+	table.SetHeader(header)
+	table.SetAutoWrapText(false)
+	table.SetAutoFormatHeaders(false)
+	table.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetBorder(true)
+	table.SetColumnSeparator(" ")
+	table.SetCenterSeparator(" ")
+	return table
+}
+
+// formatValue formats a value read from the plan, empty if there is none
+func formatValue(value interface{}) string {
+	if value == nil {
+		return ""
+	}
+	return fmt.Sprint(value)
+}
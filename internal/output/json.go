@@ -4,16 +4,48 @@ import (
 	"encoding/json"
 
 	"github.com/carboniferio/carbonifer/internal/estimate/estimation"
+	"github.com/carboniferio/carbonifer/internal/resources"
 	log "github.com/sirupsen/logrus"
 )
 
-// GenerateReportJSON generates a JSON report from an estimation report
-func GenerateReportJSON(estimations estimation.EstimationReport) string {
+// GenerateReportJSON generates a JSON report from an estimation report.
+// The provenance of the properties of resources is only included with provenance, set by config `out.provenance`.
+func GenerateReportJSON(estimations estimation.EstimationReport, provenance bool) string {
 	log.Debug("Generating JSON report")
 
+	if !provenance {
+		estimations = withoutProvenance(estimations)
+	}
 	reportTextBytes, err := json.MarshalIndent(estimations, "", "  ")
 	if err != nil {
 		log.Fatal(err)
 	}
 	return string(reportTextBytes)
 }
+
+// withoutProvenance returns a copy of the estimation report without the provenance of the properties of its resources
+func withoutProvenance(estimations estimation.EstimationReport) estimation.EstimationReport {
+	estimationResources := make([]estimation.EstimationResource, len(estimations.Resources))
+	for i, estimationResource := range estimations.Resources {
+		switch resource := estimationResource.Resource.(type) {
+		case resources.ComputeResource:
+			estimationResource.Resource = computeResourceWithoutProvenance(resource)
+		case *resources.ComputeResource:
+			computeResource := computeResourceWithoutProvenance(*resource)
+			estimationResource.Resource = &computeResource
+		}
+		estimationResources[i] = estimationResource
+	}
+	estimations.Resources = estimationResources
+	return estimations
+}
+
+func computeResourceWithoutProvenance(resource resources.ComputeResource) resources.ComputeResource {
+	if resource.Specs == nil || resource.Specs.Provenance == nil {
+		return resource
+	}
+	specs := *resource.Specs
+	specs.Provenance = nil
+	resource.Specs = &specs
+	return resource
+}
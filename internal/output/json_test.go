@@ -1,6 +1,7 @@
 package output
 
 import (
+	"fmt"
 	"io"
 	"os"
 	"path"
@@ -14,6 +15,8 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/carboniferio/carbonifer/internal/estimate/estimation"
+	"github.com/carboniferio/carbonifer/internal/providers"
+	"github.com/carboniferio/carbonifer/internal/resources"
 	"github.com/carboniferio/carbonifer/internal/testutils"
 	_ "github.com/carboniferio/carbonifer/internal/testutils"
 )
@@ -28,13 +31,16 @@ func TestGenerateReportJson_Empty(t *testing.T) {
 			UnitTime:                "h",
 			UnitWattTime:            "w",
 			UnitCarbonEmissionsTime: "gCO2eq/h",
+			UnitWaterTime:           "L/h",
 			DateTime:                now,
 		},
 		Resources: []estimation.EstimationResource{},
 		Total: estimation.EstimationTotal{
-			Power:           decimal.Decimal{},
-			CarbonEmissions: decimal.Decimal{},
-			ResourcesCount:  decimal.Zero,
+			Power:                decimal.Decimal{},
+			CarbonEmissions:      decimal.Decimal{},
+			ResourcesCount:       decimal.Zero,
+			MarketBasedEmissions: decimal.NewNullDecimal(decimal.Zero),
+			Water:                decimal.NewNullDecimal(decimal.Zero),
 		},
 	}
 
@@ -58,3 +64,76 @@ func loadOutput(name string) string {
 	return string(content)
 
 }
+
+func TestGenerateReportJSON_Provenance(t *testing.T) {
+	resource := resources.ComputeResource{
+		Identification: &resources.ResourceIdentification{
+			Address:      "google_compute_instance.first",
+			Name:         "first",
+			ResourceType: "google_compute_instance",
+			Provider:     providers.GCP,
+			Region:       "europe-west9",
+			Count:        1,
+		},
+		Specs: &resources.ComputeResourceSpecs{
+			VCPUs: 2,
+			Provenance: map[string]resources.Provenance{
+				"vCPUs": {Source: resources.ProvenanceDataFile, Path: ".values.machine_type", Reference: "gcp_instances.json (.vcpus)"},
+			},
+		},
+	}
+	estimations := estimation.EstimationReport{
+		Resources: []estimation.EstimationResource{{Resource: &resource}},
+	}
+
+	assert.NotContains(t, GenerateReportJSON(estimations, false), "Provenance")
+	// The resource itself keeps its provenance
+	assert.NotNil(t, resource.Specs.Provenance)
+
+	got := GenerateReportJSON(estimations, true)
+	assert.Contains(t, got, `"Provenance": {`)
+	assert.Contains(t, got, `"Reference": "gcp_instances.json (.vcpus)"`)
+}
+
+func TestGenerateReportJSON_FailedResource(t *testing.T) {
+	resource := resources.FailedResource{
+		Identification: &resources.ResourceIdentification{
+			Address:      "aws_instance.web",
+			Name:         "web",
+			ResourceType: "aws_instance",
+			Provider:     providers.AWS,
+			Count:        1,
+		},
+		Err: &providers.UnknownInstanceTypeError{Provider: providers.AWS, InstanceType: "x9.large"},
+	}
+	estimations := estimation.EstimationReport{
+		Resources: []estimation.EstimationResource{{Resource: resource, Error: resource.Err.Error()}},
+	}
+
+	got := GenerateReportJSON(estimations, false)
+	assert.Contains(t, got, `"Address": "aws_instance.web"`)
+	assert.Contains(t, got, fmt.Sprintf(`"Err": %q`, resource.Err.Error()))
+	assert.Contains(t, resource.Err.Error(), "x9.large")
+}
+
+func TestGenerateReportText_Spot(t *testing.T) {
+	resource := resources.ComputeResource{
+		Identification: &resources.ResourceIdentification{
+			Address:      "google_compute_instance.batch",
+			Name:         "batch",
+			ResourceType: "google_compute_instance",
+			Provider:     providers.GCP,
+			Region:       "europe-west9",
+			Count:        1,
+			Spot:         true,
+		},
+		Specs: &resources.ComputeResourceSpecs{VCPUs: 2},
+	}
+	estimations := estimation.EstimationReport{
+		Resources: []estimation.EstimationResource{{Resource: resource, MarketBasedEmissions: decimal.NewNullDecimal(decimal.Zero)}},
+		Total:     estimation.EstimationTotal{MarketBasedEmissions: decimal.NewNullDecimal(decimal.Zero)},
+	}
+
+	got := GenerateReportText(estimations)
+	assert.Contains(t, got, "Spot or preemptible instances: \n\n  google_compute_instance.batch\n")
+}
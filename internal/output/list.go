@@ -0,0 +1,43 @@
+package output
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+	log "github.com/sirupsen/logrus"
+)
+
+// GenerateListText generates a text table of a list, such as the supported resource types or regions
+func GenerateListText(title string, header []string, rows [][]string) string {
+	log.Debug("Generating text list")
+	tableString := &strings.Builder{}
+	tableString.WriteString("\n  " + title + ": \n\n")
+
+	table := tablewriter.NewWriter(tableString)
+	table.SetHeader(header)
+	table.SetAutoWrapText(false)
+	table.AppendBulk(rows)
+
+	// Format
+	table.SetAutoFormatHeaders(false)
+	table.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetBorder(true)
+	table.SetColumnSeparator(" ")
+	table.SetCenterSeparator(" ")
+
+	table.Render()
+	return tableString.String()
+}
+
+// GenerateListJSON generates a JSON array of a list
+func GenerateListJSON(list interface{}) string {
+	log.Debug("Generating JSON list")
+
+	listBytes, err := json.MarshalIndent(list, "", "  ")
+	if err != nil {
+		log.Fatal(err)
+	}
+	return string(listBytes)
+}
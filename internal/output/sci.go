@@ -0,0 +1,52 @@
+package output
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/carboniferio/carbonifer/internal/estimate/estimation"
+	"github.com/olekukonko/tablewriter"
+	log "github.com/sirupsen/logrus"
+)
+
+// GenerateReportSCI generates a text report of the Software Carbon Intensity (SCI) of an estimation report
+func GenerateReportSCI(report estimation.EstimationReport, sciReport estimation.SCIReport) string {
+	log.Debug("Generating SCI report")
+	tableString := &strings.Builder{}
+	tableString.WriteString(fmt.Sprintf(
+		"\n  Software Carbon Intensity (SCI), ((E * I) + M) per R, with R = %v %v per %v: \n\n",
+		sciReport.FunctionalUnitCount.String(),
+		sciReport.FunctionalUnit,
+		report.Info.UnitTime,
+	))
+
+	table := tablewriter.NewWriter(tableString)
+	table.SetHeader([]string{"resource", "operational (E * I)", "embodied (M)", "SCI", "water per R"})
+
+	for _, resource := range sciReport.Resources {
+		table.Append(formatSCIResource(resource, report.Info.UnitCarbonEmissionsTime, sciReport.UnitSCI, sciReport.FunctionalUnit))
+	}
+	table.SetFooter(formatSCIResource(sciReport.Total, report.Info.UnitCarbonEmissionsTime, sciReport.UnitSCI, sciReport.FunctionalUnit))
+
+	// Format
+	table.SetAutoFormatHeaders(false)
+	table.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetFooterAlignment(tablewriter.ALIGN_LEFT)
+	table.SetBorder(true)
+	table.SetColumnSeparator(" ")
+	table.SetCenterSeparator(" ")
+
+	table.Render()
+	return tableString.String()
+}
+
+func formatSCIResource(resource estimation.SCIResource, unitCarbonEmissionsTime string, unitSCI string, functionalUnit string) []string {
+	return []string{
+		resource.Address,
+		fmt.Sprintf(" %v %v", resource.OperationalEmissions.StringFixed(4), unitCarbonEmissionsTime),
+		fmt.Sprintf(" %v %v", resource.EmbodiedEmissions.StringFixed(4), unitCarbonEmissionsTime),
+		fmt.Sprintf(" %v %v", resource.SCI.StringFixed(6), unitSCI),
+		formatNullDecimal(resource.Water, 6, "L/"+functionalUnit),
+	}
+}
@@ -6,7 +6,9 @@ import (
 
 	"github.com/carboniferio/carbonifer/internal/estimate"
 	"github.com/carboniferio/carbonifer/internal/estimate/estimation"
+	"github.com/carboniferio/carbonifer/internal/resources"
 	"github.com/olekukonko/tablewriter"
+	"github.com/shopspring/decimal"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -17,18 +19,33 @@ func GenerateReportText(report estimation.EstimationReport) string {
 	tableString.WriteString("\n  Average estimation of CO2 emissions per instance: \n\n")
 
 	table := tablewriter.NewWriter(tableString)
-	table.SetHeader([]string{"resource", "count", "replicas", "emissions per instance"})
+	table.SetHeader([]string{"resource", "count", "replicas", "emissions per instance", "range per instance", "market-based per instance", "water per instance"})
 
 	// Default sort
 	estimations := report.Resources
 	estimate.SortEstimations(&estimations)
 
 	for _, resource := range report.Resources {
+		if resource.Error != "" {
+			table.Append([]string{
+				resource.Resource.GetAddress(),
+				"",
+				"",
+				"error",
+				"",
+				"",
+				"",
+			})
+			continue
+		}
 		table.Append([]string{
 			resource.Resource.GetAddress(),
 			fmt.Sprintf("%v", resource.Resource.GetIdentification().Count),
 			fmt.Sprintf("%v", resource.Resource.GetIdentification().ReplicationFactor),
 			fmt.Sprintf(" %v %v", resource.CarbonEmissions.StringFixed(4), report.Info.UnitCarbonEmissionsTime),
+			formatRange(resource.CarbonEmissionsRange, report.Info.UnitCarbonEmissionsTime),
+			formatNullDecimal(resource.MarketBasedEmissions, 4, report.Info.UnitCarbonEmissionsTime),
+			formatNullDecimal(resource.Water, 4, report.Info.UnitWaterTime),
 		})
 	}
 
@@ -38,10 +55,33 @@ func GenerateReportText(report estimation.EstimationReport) string {
 			"",
 			"",
 			"unsupported",
+			"",
+			"",
+			"",
+		})
+	}
+
+	if !report.Total.NetworkCarbonEmissions.IsZero() {
+		table.Append([]string{
+			"Networking",
+			"",
+			"",
+			fmt.Sprintf(" %v %v", report.Total.NetworkCarbonEmissions.StringFixed(4), report.Info.UnitCarbonEmissionsTime),
+			"",
+			formatNullDecimal(report.Total.NetworkMarketBasedEmissions, 4, report.Info.UnitCarbonEmissionsTime),
+			"",
 		})
 	}
 
-	table.SetFooter([]string{"Total", report.Total.ResourcesCount.String(), "", fmt.Sprintf(" %v %v", report.Total.CarbonEmissions.StringFixed(4), report.Info.UnitCarbonEmissionsTime)})
+	table.SetFooter([]string{
+		"Total",
+		report.Total.ResourcesCount.String(),
+		"",
+		fmt.Sprintf(" %v %v", report.Total.CarbonEmissions.StringFixed(4), report.Info.UnitCarbonEmissionsTime),
+		formatRange(report.Total.CarbonEmissionsRange, report.Info.UnitCarbonEmissionsTime),
+		formatNullDecimal(report.Total.MarketBasedEmissions, 4, report.Info.UnitCarbonEmissionsTime),
+		formatNullDecimal(report.Total.Water, 4, report.Info.UnitWaterTime),
+	})
 
 	// Format
 	table.SetAutoFormatHeaders(false)
@@ -53,5 +93,159 @@ func GenerateReportText(report estimation.EstimationReport) string {
 	table.SetCenterSeparator(" ")
 
 	table.Render()
+
+	writeDataQuality(report, tableString)
+	writeErrors(report, tableString)
+	writeUtilizationOverrides(report, tableString)
+	writeCPUPlatforms(report, tableString)
+	writeSpotInstances(report, tableString)
+
+	if !report.Total.NetworkCarbonEmissions.IsZero() {
+		generateNetworkingTable(report, tableString)
+	}
 	return tableString.String()
 }
+
+// formatRange formats the low and high bounds of an estimation
+func formatRange(estimationRange estimation.EstimationRange, unit string) string {
+	return fmt.Sprintf(" %v - %v %v", estimationRange.Low.StringFixed(4), estimationRange.High.StringFixed(4), unit)
+}
+
+// formatNullDecimal formats a value that can be unknown, such as market-based emissions or water
+func formatNullDecimal(value decimal.NullDecimal, places int32, unit string) string {
+	if !value.Valid {
+		return " unknown"
+	}
+	return fmt.Sprintf(" %v %v", value.Decimal.StringFixed(places), unit)
+}
+
+// writeErrors lists the resources that could not be estimated, with the reason
+func writeErrors(report estimation.EstimationReport, tableString *strings.Builder) {
+	errors := []string{}
+	for _, resource := range report.Resources {
+		if resource.Error != "" {
+			errors = append(errors, fmt.Sprintf("  %v: %v\n", resource.Resource.GetAddress(), resource.Error))
+		}
+	}
+	if len(errors) == 0 {
+		return
+	}
+	tableString.WriteString("\n  Resources not estimated: \n\n")
+	for _, err := range errors {
+		tableString.WriteString(err)
+	}
+}
+
+// writeDataQuality summarises the resources estimated with specs that are default values or were not found, with their warnings
+func writeDataQuality(report estimation.EstimationReport, tableString *strings.Builder) {
+	if report.Total.ResourcesWithDefaults == 0 {
+		return
+	}
+	estimated := 0
+	for _, resource := range report.Resources {
+		if resource.Error == "" {
+			estimated++
+		}
+	}
+	tableString.WriteString(fmt.Sprintf("\n  %v of %v resources estimated with default or missing specs: \n\n", report.Total.ResourcesWithDefaults, estimated))
+	for _, resource := range report.Resources {
+		if len(resource.Warnings) == 0 {
+			continue
+		}
+		tableString.WriteString(fmt.Sprintf("  %v (%v data quality): %v\n", resource.Resource.GetAddress(), resource.DataQuality, strings.Join(resource.Warnings, ", ")))
+	}
+}
+
+// writeUtilizationOverrides lists the utilizations that are not the provider defaults
+func writeUtilizationOverrides(report estimation.EstimationReport, tableString *strings.Builder) {
+	overrides := []string{}
+	for _, resource := range report.Resources {
+		usages := []string{}
+		if resource.AverageCPUUsageSource != "" && resource.AverageCPUUsageSource != resources.SourceConfig {
+			usages = append(usages, fmt.Sprintf("CPU %v%% (%v)", resource.AverageCPUUsage.Mul(decimal.NewFromInt(100)).String(), resource.AverageCPUUsageSource))
+		}
+		if resource.AverageGPUUsageSource != "" && resource.AverageGPUUsageSource != resources.SourceConfig {
+			usages = append(usages, fmt.Sprintf("GPU %v%% (%v)", resource.AverageGPUUsage.Mul(decimal.NewFromInt(100)).String(), resource.AverageGPUUsageSource))
+		}
+		if len(usages) > 0 {
+			overrides = append(overrides, fmt.Sprintf("  %v: %v\n", resource.Resource.GetAddress(), strings.Join(usages, ", ")))
+		}
+	}
+	if len(overrides) == 0 {
+		return
+	}
+	tableString.WriteString("\n  Average utilization set per resource: \n\n")
+	for _, override := range overrides {
+		tableString.WriteString(override)
+	}
+}
+
+// writeCPUPlatforms lists the CPU platforms assumed instead of the flat coefficients of the provider
+func writeCPUPlatforms(report estimation.EstimationReport, tableString *strings.Builder) {
+	platforms := []string{}
+	for _, resource := range report.Resources {
+		if resource.CPUPlatform != "" {
+			platforms = append(platforms, fmt.Sprintf("  %v: %v\n", resource.Resource.GetAddress(), resource.CPUPlatform))
+		}
+	}
+	if len(platforms) == 0 {
+		return
+	}
+	tableString.WriteString("\n  CPU platform assumed per resource: \n\n")
+	for _, platform := range platforms {
+		tableString.WriteString(platform)
+	}
+}
+
+// writeSpotInstances lists the resources declared spot or preemptible in the assumptions file, their estimation being the same
+func writeSpotInstances(report estimation.EstimationReport, tableString *strings.Builder) {
+	spots := []string{}
+	for _, resource := range report.Resources {
+		if resource.Resource.GetIdentification().Spot {
+			spots = append(spots, fmt.Sprintf("  %v\n", resource.Resource.GetAddress()))
+		}
+	}
+	if len(spots) == 0 {
+		return
+	}
+	tableString.WriteString("\n  Spot or preemptible instances: \n\n")
+	for _, spot := range spots {
+		tableString.WriteString(spot)
+	}
+}
+
+func generateNetworkingTable(report estimation.EstimationReport, tableString *strings.Builder) {
+	tableString.WriteString("\n  Average estimation of CO2 emissions of networking: \n\n")
+
+	table := tablewriter.NewWriter(tableString)
+	table.SetHeader([]string{"resource", "data transfer", "emissions", "range"})
+
+	for _, resource := range report.Resources {
+		if resource.NetworkCarbonEmissions.IsZero() {
+			continue
+		}
+		computeResource, ok := resource.Resource.(*resources.ComputeResource)
+		if !ok {
+			continue
+		}
+		table.Append([]string{
+			resource.Resource.GetAddress(),
+			fmt.Sprintf("%v GB/month", computeResource.Specs.NetworkEgressGb),
+			fmt.Sprintf(" %v %v", resource.NetworkCarbonEmissions.StringFixed(4), report.Info.UnitCarbonEmissionsTime),
+			formatRange(resource.NetworkCarbonEmissionsRange, report.Info.UnitCarbonEmissionsTime),
+		})
+	}
+
+	table.SetFooter([]string{"Total", "", fmt.Sprintf(" %v %v", report.Total.NetworkCarbonEmissions.StringFixed(4), report.Info.UnitCarbonEmissionsTime), ""})
+
+	// Format
+	table.SetAutoFormatHeaders(false)
+	table.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetFooterAlignment(tablewriter.ALIGN_LEFT)
+	table.SetBorder(true)
+	table.SetColumnSeparator(" ")
+	table.SetCenterSeparator(" ")
+
+	table.Render()
+}
@@ -0,0 +1,246 @@
+package plan
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/carboniferio/carbonifer/internal/resources"
+	"github.com/carboniferio/carbonifer/internal/utils"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+)
+
+// ResourceAssumptions are the assumptions of a resource set in the assumptions file, nil values keep the provider defaults
+type ResourceAssumptions struct {
+	AvgCPUUse                *float64  `yaml:"avg_cpu_use,omitempty"`
+	AvgGPUUse                *float64  `yaml:"avg_gpu_use,omitempty"`
+	AvgAutoscalerSizePercent *float64  `yaml:"avg_autoscaler_size_percent,omitempty"`
+	AvgBucketSizeGb          *float64  `yaml:"avg_bucket_size_gb,omitempty"`
+	HoursPerDay              *float64  `yaml:"hours_per_day,omitempty"`
+	StorageFill              *float64  `yaml:"storage_fill,omitempty"`
+	Spot                     *bool     `yaml:"spot,omitempty"`
+	Schedule                 *Schedule `yaml:"schedule,omitempty"`
+}
+
+// Schedule is an operating schedule, with cron expressions of start and stop events
+type Schedule struct {
+	Start string `yaml:"start"`
+	Stop  string `yaml:"stop"`
+}
+
+// Assumptions is the content of the assumptions file, assumptions by resource type and by resource address.
+// Keys are glob patterns (`*` matches any sequence of characters, `?` any character)
+type Assumptions struct {
+	Types     map[string]ResourceAssumptions `yaml:"types,omitempty"`
+	Resources map[string]ResourceAssumptions `yaml:"resources,omitempty"`
+}
+
+// LoadAssumptions reads and validates the assumptions file set in config `assumptions_file`, if any
+func LoadAssumptions(cfg *viper.Viper) (*Assumptions, error) {
+	assumptions := &Assumptions{}
+	assumptionsFile := cfg.GetString("assumptions_file")
+	if assumptionsFile == "" {
+		return assumptions, nil
+	}
+	log.Debugf("Reading assumptions file %v", assumptionsFile)
+	content, err := os.ReadFile(assumptionsFile)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Cannot read assumptions file %v", assumptionsFile)
+	}
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	decoder.KnownFields(true)
+	err = decoder.Decode(assumptions)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, errors.Wrapf(err, "Cannot parse assumptions file %v", assumptionsFile)
+	}
+	err = assumptions.validate()
+	if err != nil {
+		return nil, errors.Wrapf(err, "Invalid assumptions file %v", assumptionsFile)
+	}
+	return assumptions, nil
+}
+
+func (a *Assumptions) validate() error {
+	for _, patterns := range []map[string]ResourceAssumptions{a.Types, a.Resources} {
+		for pattern, resourceAssumptions := range patterns {
+			err := resourceAssumptions.validate()
+			if err != nil {
+				return errors.Wrapf(err, "Invalid assumptions for '%v'", pattern)
+			}
+		}
+	}
+	for pattern, resourceAssumptions := range a.Types {
+		if resourceAssumptions.Spot != nil && !matchesAny(pattern, spotResourceTypes) {
+			return errors.Errorf("Invalid assumptions for '%v': spot applies to instances only, such as %v", pattern, strings.Join(spotResourceTypes, ", "))
+		}
+	}
+	return nil
+}
+
+// spotResourceTypes are the resource types whose instances can be spot or preemptible
+var spotResourceTypes = []string{
+	"aws_autoscaling_group",
+	"aws_instance",
+	"aws_launch_template",
+	"google_compute_instance",
+	"google_compute_instance_from_template",
+	"google_compute_instance_group_manager",
+	"google_compute_region_instance_group_manager",
+	"google_container_node_pool",
+}
+
+func (ra *ResourceAssumptions) validate() error {
+	ratios := map[string]*float64{
+		"avg_cpu_use":                 ra.AvgCPUUse,
+		"avg_gpu_use":                 ra.AvgGPUUse,
+		"avg_autoscaler_size_percent": ra.AvgAutoscalerSizePercent,
+		"storage_fill":                ra.StorageFill,
+	}
+	for name, value := range ratios {
+		if value != nil && (*value < 0 || *value > 1) {
+			return errors.Errorf("%v must be between 0 and 1: %v", name, *value)
+		}
+	}
+	if ra.HoursPerDay != nil && (*ra.HoursPerDay <= 0 || *ra.HoursPerDay > 24) {
+		return errors.Errorf("hours_per_day must be greater than 0 and at most 24: %v", *ra.HoursPerDay)
+	}
+	if ra.AvgBucketSizeGb != nil && *ra.AvgBucketSizeGb < 0 {
+		return errors.Errorf("avg_bucket_size_gb must be positive: %v", *ra.AvgBucketSizeGb)
+	}
+	if ra.Schedule != nil {
+		_, err := utils.WeeklyRunningRatio([]string{ra.Schedule.Start}, []string{ra.Schedule.Stop})
+		if err != nil {
+			return errors.Wrap(err, "Invalid schedule")
+		}
+	}
+	return nil
+}
+
+// UnmatchedPatterns returns the patterns of the assumptions file that match no resource type or address
+func (a *Assumptions) UnmatchedPatterns(resourceTypes []string, resourceAddresses []string) []string {
+	unmatched := []string{}
+	for pattern := range a.Types {
+		if !matchesAny(pattern, resourceTypes) {
+			unmatched = append(unmatched, "types."+pattern)
+		}
+	}
+	for pattern := range a.Resources {
+		if !matchesAny(pattern, resourceAddresses) {
+			unmatched = append(unmatched, "resources."+pattern)
+		}
+	}
+	sort.Strings(unmatched)
+	return unmatched
+}
+
+// UnmatchedResources returns the patterns that match no resource of the plan
+func (a *Assumptions) UnmatchedResources(resourcesMap map[string]resources.Resource) []string {
+	resourceTypes := []string{}
+	resourceAddresses := []string{}
+	for _, resource := range resourcesMap {
+		resourceTypes = append(resourceTypes, resource.GetIdentification().ResourceType)
+		resourceAddresses = append(resourceAddresses, resource.GetAddress())
+	}
+	return a.UnmatchedPatterns(resourceTypes, resourceAddresses)
+}
+
+func matchesAny(pattern string, values []string) bool {
+	for _, value := range values {
+		if matchPattern(pattern, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchPattern matches a glob pattern where `*` matches any sequence of characters and `?` any character.
+// Other characters, like brackets of indexed addresses, are matched literally
+func matchPattern(pattern string, value string) bool {
+	regexPattern := regexp.QuoteMeta(pattern)
+	regexPattern = strings.ReplaceAll(regexPattern, `\*`, ".*")
+	regexPattern = strings.ReplaceAll(regexPattern, `\?`, ".")
+	matched, err := regexp.MatchString("^"+regexPattern+"$", value)
+	return err == nil && matched
+}
+
+// resourceAssumptions merges the assumptions matching the resource, by type then by address.
+// When several patterns match, the longest (most specific) one wins.
+func (a *Assumptions) resourceAssumptions(resourceType string, resourceAddress string) *ResourceAssumptions {
+	merged := &ResourceAssumptions{}
+	if a == nil {
+		return merged
+	}
+	mergeMatchingAssumptions(merged, a.Types, resourceType)
+	mergeMatchingAssumptions(merged, a.Resources, resourceAddress)
+	return merged
+}
+
+func mergeMatchingAssumptions(merged *ResourceAssumptions, patterns map[string]ResourceAssumptions, value string) {
+	matchingPatterns := []string{}
+	for pattern := range patterns {
+		if matchPattern(pattern, value) {
+			matchingPatterns = append(matchingPatterns, pattern)
+		}
+	}
+	sort.Slice(matchingPatterns, func(i, j int) bool {
+		if len(matchingPatterns[i]) == len(matchingPatterns[j]) {
+			return matchingPatterns[i] < matchingPatterns[j]
+		}
+		return len(matchingPatterns[i]) < len(matchingPatterns[j])
+	})
+	for _, pattern := range matchingPatterns {
+		merged.merge(patterns[pattern])
+	}
+}
+
+func (ra *ResourceAssumptions) merge(other ResourceAssumptions) {
+	if other.AvgCPUUse != nil {
+		ra.AvgCPUUse = other.AvgCPUUse
+	}
+	if other.AvgGPUUse != nil {
+		ra.AvgGPUUse = other.AvgGPUUse
+	}
+	if other.AvgAutoscalerSizePercent != nil {
+		ra.AvgAutoscalerSizePercent = other.AvgAutoscalerSizePercent
+	}
+	if other.AvgBucketSizeGb != nil {
+		ra.AvgBucketSizeGb = other.AvgBucketSizeGb
+	}
+	if other.HoursPerDay != nil {
+		ra.HoursPerDay = other.HoursPerDay
+	}
+	if other.StorageFill != nil {
+		ra.StorageFill = other.StorageFill
+	}
+	if other.Spot != nil {
+		ra.Spot = other.Spot
+	}
+	if other.Schedule != nil {
+		ra.Schedule = other.Schedule
+	}
+}
+
+// configValue returns the assumption overriding the provider config key (`provider.<provider>.<name>`) for the resource, if any
+func (ra *ResourceAssumptions) configValue(configKey string) *float64 {
+	keyParts := strings.Split(configKey, ".")
+	if len(keyParts) != 3 || keyParts[0] != "provider" {
+		return nil
+	}
+	switch keyParts[2] {
+	case "avg_cpu_use":
+		return ra.AvgCPUUse
+	case "avg_gpu_use":
+		return ra.AvgGPUUse
+	case "avg_autoscaler_size_percent":
+		return ra.AvgAutoscalerSizePercent
+	case "avg_bucket_size_gb":
+		return ra.AvgBucketSizeGb
+	}
+	return nil
+}
@@ -2,7 +2,7 @@ package plan
 
 import "fmt"
 
-// ENUM(SSD, HDD)
+// ENUM(SSD, HDD, ARCHIVE)
 //
 //go:generate go-enum --nocase --noprefix --marshal
 type DiskType int
@@ -18,15 +18,18 @@ const (
 	SSD DiskType = iota
 	// HDD is a DiskType of type HDD.
 	HDD
+	// ARCHIVE is a DiskType of type ARCHIVE.
+	ARCHIVE
 )
 
 var ErrInvalidDiskType = errors.New("not a valid DiskType")
 
-const _DiskTypeName = "SSDHDD"
+const _DiskTypeName = "SSDHDDARCHIVE"
 
 var _DiskTypeMap = map[DiskType]string{
-	SSD: _DiskTypeName[0:3],
-	HDD: _DiskTypeName[3:6],
+	SSD:     _DiskTypeName[0:3],
+	HDD:     _DiskTypeName[3:6],
+	ARCHIVE: _DiskTypeName[6:13],
 }
 
 // String implements the Stringer interface.
@@ -38,10 +41,12 @@ func (x DiskType) String() string {
 }
 
 var _DiskTypeValue = map[string]DiskType{
-	_DiskTypeName[0:3]:                  SSD,
-	strings.ToLower(_DiskTypeName[0:3]): SSD,
-	_DiskTypeName[3:6]:                  HDD,
-	strings.ToLower(_DiskTypeName[3:6]): HDD,
+	_DiskTypeName[0:3]:                   SSD,
+	strings.ToLower(_DiskTypeName[0:3]):  SSD,
+	_DiskTypeName[3:6]:                   HDD,
+	strings.ToLower(_DiskTypeName[3:6]):  HDD,
+	_DiskTypeName[6:13]:                  ARCHIVE,
+	strings.ToLower(_DiskTypeName[6:13]): ARCHIVE,
 }
 
 // ParseDiskType attempts to convert a string to a DiskType.
@@ -0,0 +1,147 @@
+package plan
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/carboniferio/carbonifer/internal/resources"
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+)
+
+// Explanation is how a resource was read from a terraform plan: the mapping that matched it and how its properties were resolved
+type Explanation struct {
+	Address     string
+	MappingType string // resource type of the mapping that matched, such as google_compute_instance
+	MappingPath string // jq path of the mapping that selected the resource in the plan
+	Properties  []PropertyTrace
+	Resource    resources.Resource
+}
+
+// PropertyTrace is how a property of a resource was resolved: its provenance, with the values read and resolved
+type PropertyTrace struct {
+	Property string // such as vCPUs, or storage.size for a property of the items of a list
+	resources.Provenance
+	RawValue interface{} // value at the path, or default value, before regex and reference
+	Value    interface{}
+	Unit     string `json:",omitempty"`
+}
+
+// ExplainResource returns how the resource of the address was read from the terraform plan
+func ExplainResource(cfg *viper.Viper, tfPlan *map[string]interface{}, address string) (*Explanation, error) {
+	assumptions, err := LoadAssumptions(cfg)
+	if err != nil {
+		return nil, err
+	}
+	explanation := &Explanation{
+		Address:    address,
+		Properties: []PropertyTrace{},
+	}
+	resourcesMap, err := getResources(tfPlan, &planContext{Config: cfg, Assumptions: assumptions, Explanation: explanation})
+	if err != nil {
+		return nil, err
+	}
+	resource, ok := resourcesMap[address]
+	if !ok {
+		return nil, errors.Errorf("Resource %v not found in the terraform plan", address)
+	}
+	explanation.Resource = resource
+	// Properties the estimation depends on are listed even if not found, their default being used by the estimation
+	for _, property := range explainedProperties {
+		if !explanation.hasPropertyTrace(property) {
+			explanation.Properties = append(explanation.Properties, PropertyTrace{Property: property})
+		}
+	}
+	return explanation, nil
+}
+
+// explainedProperties are the properties of a resource always listed by its explanation
+var explainedProperties = []string{"vCPUs", "memory", "storage", "guest_accelerator", "count", "replication_factor"}
+
+// hasPropertyTrace returns true if the property, or a property of its items, has been recorded
+func (explanation *Explanation) hasPropertyTrace(property string) bool {
+	for _, recorded := range explanation.Properties {
+		if recorded.Property == property || strings.HasPrefix(recorded.Property, property+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// explanationOf returns the explanation being recorded for the resource of the context, nil if it is not explained
+func explanationOf(context *tfContext) *Explanation {
+	rootContext := context.RootContext
+	if rootContext == nil || rootContext.Plan == nil || rootContext.Plan.Explanation == nil {
+		return nil
+	}
+	if rootContext.Plan.Explanation.Address != rootContext.ResourceAddress {
+		return nil
+	}
+	return rootContext.Plan.Explanation
+}
+
+// traceMapping records the mapping that matched the resource, if it is the one explained
+func traceMapping(resourceAddress string, resourceType string, path string, plan *planContext) {
+	if plan == nil || plan.Explanation == nil || plan.Explanation.Address != resourceAddress {
+		return
+	}
+	plan.Explanation.MappingType = resourceType
+	plan.Explanation.MappingPath = path
+}
+
+// traceProperty records the provenance of a property, with its values, in the explanation of the resource if it is explained.
+// Only the first resolution of a property is kept, except for the items of lists.
+func traceProperty(key string, context *tfContext, propertyMapping *PropertyDefinition, provenance *resources.Provenance, rawValue interface{}, value interface{}) {
+	explanation := explanationOf(context)
+	if explanation == nil || provenance == nil {
+		return
+	}
+	prefix := strings.TrimPrefix(strings.TrimPrefix(context.ResourceAddress, context.RootContext.ResourceAddress), ".")
+	trace := PropertyTrace{
+		Property:   key,
+		Provenance: *provenance,
+		RawValue:   rawValue,
+		Value:      value,
+	}
+	if prefix != "" {
+		trace.Property = prefix + "." + key
+	}
+	isItem := prefix != "" && prefix != "variables"
+	if !isItem {
+		for _, recorded := range explanation.Properties {
+			if recorded.Property == trace.Property {
+				return
+			}
+		}
+	}
+	if propertyMapping != nil && propertyMapping.Unit != nil {
+		trace.Unit = *propertyMapping.Unit
+	}
+	explanation.Properties = append(explanation.Properties, trace)
+}
+
+// describeReference returns where a reference looks up values, such as "gcp_instances.json (.vcpus)"
+func describeReference(reference *Reference, context *tfContext) string {
+	if reference == nil {
+		return ""
+	}
+	switch {
+	case reference.JSONFile != "":
+		file := reference.JSONFile
+		generalMappings := (*context.RootContext.Plan.Mappings.General)[context.Provider]
+		if generalMappings.JSONData != nil {
+			if filename, ok := (*generalMappings.JSONData)[reference.JSONFile]; ok {
+				file = fmt.Sprint(filename)
+			}
+		}
+		if reference.Property != "" {
+			return fmt.Sprintf("%v (%v)", file, reference.Property)
+		}
+		return file
+	case reference.General != "":
+		return fmt.Sprintf("general.%v", reference.General)
+	case reference.Paths != nil:
+		return fmt.Sprintf("plan %v", strings.Join(reference.Paths, ", "))
+	}
+	return ""
+}
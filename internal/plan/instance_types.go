@@ -0,0 +1,93 @@
+package plan
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/carboniferio/carbonifer/internal/providers"
+	"github.com/carboniferio/carbonifer/internal/providers/aws"
+	"github.com/carboniferio/carbonifer/internal/providers/gcp"
+	"github.com/carboniferio/carbonifer/internal/resources"
+	"github.com/pkg/errors"
+)
+
+// Policies for resources of instance types missing from the data files, set in config `provider.<provider>.unknown_instance_type_policy`
+const (
+	UnknownInstanceTypePolicyError         = "error"          // the resource is not estimated, with suggestions of known instance types
+	UnknownInstanceTypePolicyFamilyAverage = "family_average" // estimated from the average memory per vCPU of the family of the instance type
+)
+
+// instanceDataFiles are the data files of the instance types of each provider
+var instanceDataFiles = map[providers.Provider]string{
+	providers.GCP: "gcp_instances.json",
+	providers.AWS: "aws_instances.json",
+}
+
+// checkUnknownInstanceType applies the unknown instance type policy of the provider when the vCPUs of the resource were
+// not found because its instance type is missing from the instance data file of its provider. Keys missing from other
+// data files, such as database or cache tiers, are left to the data quality of the resource.
+func checkUnknownInstanceType(computeResource *resources.ComputeResource, context *tfContext) error {
+	provenance, ok := context.Provenance["vCPUs"]
+	if !ok || provenance.Source != resources.ProvenanceNotFound {
+		return nil
+	}
+	provider := context.Provider
+	dataFile, ok := instanceDataFiles[provider]
+	if !ok || !strings.HasPrefix(provenance.Reference, dataFile) {
+		return nil
+	}
+	policyKey := fmt.Sprintf("provider.%v.unknown_instance_type_policy", strings.ToLower(provider.String()))
+	cfg := context.RootContext.Plan.Config
+	policy := cfg.GetString(policyKey)
+	dataPath := cfg.GetString("data.path")
+	switch policy {
+	case "", UnknownInstanceTypePolicyError, UnknownInstanceTypePolicyFamilyAverage:
+	default:
+		return errors.Errorf("Invalid %v '%v', must be one of %v, %v", policyKey, policy, UnknownInstanceTypePolicyError, UnknownInstanceTypePolicyFamilyAverage)
+	}
+
+	var err error
+	switch provider {
+	case providers.GCP:
+		_, err = gcp.GetGCPMachineType(dataPath, provenance.Key, "")
+	case providers.AWS:
+		_, err = aws.GetAWSInstanceType(dataPath, provenance.Key)
+	}
+	var unknownInstanceTypeError *providers.UnknownInstanceTypeError
+	if !errors.As(err, &unknownInstanceTypeError) {
+		// Found after all, or the data file cannot be read
+		return err
+	}
+	if policy != UnknownInstanceTypePolicyFamilyAverage {
+		return unknownInstanceTypeError
+	}
+
+	var vcpus, memoryMb int32
+	switch provider {
+	case providers.GCP:
+		machineType, err := gcp.GetGCPFamilyAverageMachineType(dataPath, provenance.Key)
+		if err != nil {
+			return err
+		}
+		vcpus, memoryMb = machineType.Vcpus, machineType.MemoryMb
+	case providers.AWS:
+		instanceType, err := aws.GetAWSFamilyAverageInstanceType(dataPath, provenance.Key)
+		if err != nil {
+			return err
+		}
+		vcpus, memoryMb = instanceType.VCPU, instanceType.MemoryMb
+	}
+	computeResource.Specs.VCPUs = vcpus
+	computeResource.Specs.MemoryMb = memoryMb
+
+	familyProvenance := resources.Provenance{
+		Source:      resources.ProvenanceFamilyAverage,
+		Path:        provenance.Path,
+		Reference:   fmt.Sprintf("%v family", providers.InstanceFamily(provenance.Key)),
+		Key:         provenance.Key,
+		Suggestions: unknownInstanceTypeError.Suggestions,
+	}
+	context.Provenance["vCPUs"] = familyProvenance
+	context.Provenance["memory"] = familyProvenance
+	return nil
+}
@@ -6,18 +6,14 @@ import (
 	"github.com/carboniferio/carbonifer/internal/utils"
 )
 
-func getJSON(query string, json interface{}) ([]interface{}, error) {
+func getJSON(query string, json interface{}, plan *planContext) ([]interface{}, error) {
 
-	if strings.Contains(query, "all_select(") {
-		results, err := utils.GetJSON(query, *TfPlan)
-		if len(results) > 0 && err == nil {
-			return results, nil
+	if readsPlan(query) {
+		if plan == nil || plan.TfPlan == nil {
+			// Resource read on its own, such as by GetComputeResource, there is no plan to look into
+			return nil, nil
 		}
-		return nil, err
-	}
-
-	if strings.HasPrefix(query, ".configuration") || strings.HasPrefix(query, ".prior_state") || strings.HasPrefix(query, ".planned_values") {
-		results, err := utils.GetJSON(query, *TfPlan)
+		results, err := utils.GetJSON(query, *plan.TfPlan)
 		if len(results) > 0 && err == nil {
 			return results, nil
 		}
@@ -30,3 +26,19 @@ func getJSON(query string, json interface{}) ([]interface{}, error) {
 	}
 	return nil, err
 }
+
+// Functions of the carbonifer jq module that read the whole plan rather than the resource
+var planFunctions = []string{"cbf::all_select(", "cbf::config_resources", "cbf::planned_resources("}
+
+// readsPlan returns true if the query is run on the whole plan rather than on the resource
+func readsPlan(query string) bool {
+	if strings.HasPrefix(query, ".configuration") || strings.HasPrefix(query, ".prior_state") || strings.HasPrefix(query, ".planned_values") {
+		return true
+	}
+	for _, planFunction := range planFunctions {
+		if strings.Contains(query, planFunction) {
+			return true
+		}
+	}
+	return false
+}
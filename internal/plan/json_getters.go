@@ -6,10 +6,10 @@ import (
 	"strings"
 
 	"github.com/carboniferio/carbonifer/internal/providers"
+	"github.com/carboniferio/carbonifer/internal/resources"
 	"github.com/carboniferio/carbonifer/internal/utils"
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
-	"github.com/spf13/viper"
 )
 
 // tfContext is the context of a terraform resource
@@ -19,6 +19,9 @@ type tfContext struct {
 	ResourceAddress string                 // Address of the resource in tf plan
 	RootContext     *tfContext             // Root context
 	Provider        providers.Provider
+	Plan            *planContext                    // State shared by the resources of the plan
+	Assumptions     *ResourceAssumptions            // Assumptions of the resource, set on the root context
+	Provenance      map[string]resources.Provenance // Provenance of the properties of the resource, set on the root context
 }
 
 func getString(key string, context *tfContext) (*string, error) {
@@ -90,7 +93,7 @@ func getSliceItems(context tfContext) ([]interface{}, error) {
 				return nil, err
 			}
 		}
-		jsonResults, err := getJSON(path, context.Resource)
+		jsonResults, err := getJSON(path, context.Resource, context.RootContext.Plan)
 		if err != nil {
 			return nil, errors.Wrapf(err, "Cannot get item: %v", path)
 		}
@@ -151,8 +154,9 @@ func getItem(context tfContext, itemMappingProperties *ResourceMapping, jsonResu
 }
 
 type valueWithUnit struct {
-	Value interface{}
-	Unit  *string
+	Value      interface{}
+	Unit       *string
+	Provenance *resources.Provenance // Where the value comes from
 }
 
 func readPaths(pathsProperty interface{}, pathTemplateValuesParams ...*map[string]string) ([]string, error) {
@@ -193,8 +197,14 @@ func readPaths(pathsProperty interface{}, pathTemplateValuesParams ...*map[strin
 func getValue(key string, context *tfContext) (*valueWithUnit, error) {
 
 	var valueFound interface{}
+	var resolvedPath, rawPath string
+	var rawValue interface{}
+	// Provenance of the first key not found in a data file, kept if no other mapping resolves the property
+	var notFound *resources.Provenance
+	var notFoundMapping *PropertyDefinition
+	var notFoundRawValue interface{}
 	propertiesMappings := (*context.Mapping.Properties)[key]
-	for _, propertyMapping := range propertiesMappings {
+	for i, propertyMapping := range propertiesMappings {
 		paths, err := readPaths(propertyMapping.Paths)
 		if err != nil {
 			return nil, errors.Wrapf(err, "Cannot get paths for %v", context.ResourceAddress)
@@ -213,7 +223,7 @@ func getValue(key string, context *tfContext) (*valueWithUnit, error) {
 					return nil, errors.Wrapf(err, "Cannot resolve placeholders for %v", path)
 				}
 			}
-			valueFounds, err := getJSON(path, context.Resource)
+			valueFounds, err := getJSON(path, context.Resource, context.RootContext.Plan)
 			if err != nil {
 				return nil, errors.Wrapf(err, "Cannot get value for %v", path)
 			}
@@ -225,23 +235,40 @@ func getValue(key string, context *tfContext) (*valueWithUnit, error) {
 					continue
 				}
 				valueFound = valueFounds[0]
+				resolvedPath = path
+				rawPath = pathRaw
+				rawValue = valueFound
 			}
 		}
 
+		regexApplied := false
+		lookedUpKey := ""
 		if valueFound != nil {
 			valueFoundStr, ok := valueFound.(string)
 			if ok {
+				regexApplied = propertyMapping.Regex != nil
 				valueFound, err = applyRegex(valueFoundStr, &propertyMapping, context)
+				if err != nil && notFound != nil {
+					// Another format of a key not found in a data file, such as custom machine types, the key is unknown
+					valueFound = nil
+					continue
+				}
 				if err != nil {
 					return nil, errors.Wrapf(err, "Cannot apply regex for %v", valueFoundStr)
 				}
 			}
 			valueFoundStr, ok = valueFound.(string)
 			if ok {
+				lookedUpKey = valueFoundStr
 				valueFound, err = applyReference(valueFoundStr, &propertyMapping, context)
 				if err != nil {
 					return nil, errors.Wrapf(err, "Cannot apply reference for %v", valueFoundStr)
 				}
+				if valueFound == nil && notFound == nil && propertyMapping.Reference != nil && propertyMapping.Reference.JSONFile != "" {
+					notFound = newNotFoundProvenance(&propertyMapping, resolvedPath, valueFoundStr, context)
+					notFoundMapping = &propertiesMappings[i]
+					notFoundRawValue = rawValue
+				}
 			}
 		}
 
@@ -260,14 +287,19 @@ func getValue(key string, context *tfContext) (*valueWithUnit, error) {
 		}
 
 		if valueFound != nil {
+			provenance := newProvenance(&propertyMapping, rawPath, resolvedPath, regexApplied, lookedUpKey, context)
+			recordProperty(key, context, &propertyMapping, provenance, rawValue, valueFound)
 			return &valueWithUnit{
-				Value: valueFound,
-				Unit:  unit,
+				Value:      valueFound,
+				Unit:       unit,
+				Provenance: provenance,
 			}, nil
 		}
 	}
 
 	if valueFound == nil {
+		// Recorded first, a default value does not make the key found
+		recordProperty(key, context, notFoundMapping, notFound, notFoundRawValue, nil)
 		defaultValue, err := getDefaultValue(key, context)
 		if err != nil {
 			return nil, errors.Wrapf(err, "Cannot get default value for %v", key)
@@ -321,6 +353,8 @@ func resolvePlaceholders(input string, context *tfContext) (string, error) {
 	return resolvedString, nil
 }
 
+var jqStringEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
 func resolvePlaceholder(expression string, context *tfContext) (*string, error) {
 	if strings.HasPrefix(expression, "this.") {
 		thisProperty := strings.TrimPrefix(expression, "this")
@@ -339,10 +373,20 @@ func resolvePlaceholder(expression string, context *tfContext) (*string, error)
 			return nil, nil
 		}
 		valueStr := fmt.Sprintf("%v", value[0])
+		if _, ok := value[0].(string); ok {
+			// Placeholders of strings are within jq string literals, such as addresses with a for_each key
+			valueStr = jqStringEscaper.Replace(valueStr)
+		}
 		return &valueStr, err
 	} else if strings.HasPrefix(expression, "config.") {
 		configProperty := strings.TrimPrefix(expression, "config.")
-		value := viper.GetFloat64(configProperty)
+		value := context.RootContext.Plan.Config.GetFloat64(configProperty)
+		if context.RootContext != nil && context.RootContext.Assumptions != nil {
+			assumption := context.RootContext.Assumptions.configValue(configProperty)
+			if assumption != nil {
+				value = *assumption
+			}
+		}
 		valueStr := fmt.Sprintf("%v", value)
 		return &valueStr, nil
 	}
@@ -390,9 +434,12 @@ func getDefaultValue(key string, context *tfContext) (*valueWithUnit, error) {
 			}
 
 			if valueFound != nil {
+				provenance := newDefaultProvenance(&propertyMapping, context)
+				recordProperty(key, context, &propertyMapping, provenance, propertyMapping.Default, valueFound)
 				return &valueWithUnit{
-					Value: valueFound,
-					Unit:  unit,
+					Value:      valueFound,
+					Unit:       unit,
+					Provenance: provenance,
 				}, nil
 			}
 			return nil, nil
@@ -1,38 +1,58 @@
 package plan
 
 import (
+	"embed"
+	"io/fs"
 	"os"
-	"path/filepath"
+	"path"
+	"sort"
+	"strings"
+	"sync"
 
 	"github.com/carboniferio/carbonifer/internal/providers"
+	"github.com/pkg/errors"
 	"github.com/polkeli/yaml/v3" // TODO use go-yaml https://github.com/go-yaml/yaml/issues/100#issuecomment-1632853107
+	"github.com/spf13/viper"
 	"golang.org/x/exp/maps"
 )
 
-// Mapping is the mapping of the terraform resources
-var globalMappings *Mappings
+// loadedMappings are the mappings of the terraform resources per mappings directory, "" for the embedded ones
+var loadedMappings = map[string]*Mappings{}
+var loadedMappingsMutex sync.Mutex
 
-// GetMapping returns the mapping of the terraform resources
-func GetMapping() (*Mappings, error) {
-	if globalMappings != nil {
-		return globalMappings, nil
+// embeddedMappings are the mappings used when no mappings directory is set
+//
+//go:embed mappings
+var embeddedMappings embed.FS
+
+// GetMapping returns the mapping of the terraform resources, from the mappings directory set in config `mappings.path`
+func GetMapping(cfg *viper.Viper) (*Mappings, error) {
+	mappingsPath := cfg.GetString("mappings.path")
+	loadedMappingsMutex.Lock()
+	defer loadedMappingsMutex.Unlock()
+	if mappings, ok := loadedMappings[mappingsPath]; ok {
+		return mappings, nil
 	}
-	err := loadMappings()
+	mappings, err := loadMappings(mappingsPath)
 	if err != nil {
 		return nil, err
 	}
-	return globalMappings, nil
+	loadedMappings[mappingsPath] = mappings
+	return mappings, nil
 }
 
-func loadMappings() error {
-	globalMappings = &Mappings{
+func loadMappings(mappingsPath string) (*Mappings, error) {
+	mappings := &Mappings{
 		General:         &map[providers.Provider]GeneralConfig{},
 		ComputeResource: &map[string]ResourceMapping{},
 	}
-	mappingsPath := "internal/plan/mappings"
-	files, err := os.ReadDir(mappingsPath)
+	mappingsFS, err := mappingsFSOf(mappingsPath)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	files, err := fs.ReadDir(mappingsFS, ".")
+	if err != nil {
+		return nil, err
 	}
 
 	// Iterate over each entry
@@ -40,20 +60,65 @@ func loadMappings() error {
 		// Check if it's a directory
 		if file.IsDir() {
 			// Get the relative path
-			relativePath := filepath.Join(mappingsPath, file.Name())
+			relativePath := file.Name()
 
 			// Process the subfolder
-			err := loadMapping(relativePath)
+			err := loadMapping(mappingsFS, relativePath, mappings)
 			if err != nil {
-				return err
+				return nil, err
 			}
 		}
 	}
-	return nil
+	return mappings, nil
+}
+
+// ResourceTypes returns per provider the terraform resource types estimated by the mappings, and the ones ignored,
+// as names or regular expressions, both sorted
+func ResourceTypes(cfg *viper.Viper) (map[providers.Provider][]string, map[providers.Provider][]string, error) {
+	mappings, err := GetMapping(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	supported := map[providers.Provider][]string{}
+	for resourceType := range *mappings.ComputeResource {
+		// Terraform resource types are prefixed by their provider, such as google_compute_instance
+		providerName, _, _ := strings.Cut(resourceType, "_")
+		provider, err := parseProvider(providerName)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "Cannot get provider of resource type %v", resourceType)
+		}
+		supported[provider] = append(supported[provider], resourceType)
+	}
+	ignored := map[providers.Provider][]string{}
+	for provider, general := range *mappings.General {
+		if general.IgnoredResources != nil {
+			ignored[provider] = append(ignored[provider], *general.IgnoredResources...)
+		}
+	}
+	for _, resourceTypes := range supported {
+		sort.Strings(resourceTypes)
+	}
+	for _, resourceTypes := range ignored {
+		sort.Strings(resourceTypes)
+	}
+	return supported, ignored, nil
+}
+
+// mappingsFS returns the mappings directory set in config `mappings.path`, or else the embedded mappings
+func mappingsFS(cfg *viper.Viper) (fs.FS, error) {
+	return mappingsFSOf(cfg.GetString("mappings.path"))
+}
+
+// mappingsFSOf returns the mappings directory, or the embedded mappings if empty
+func mappingsFSOf(mappingsPath string) (fs.FS, error) {
+	if mappingsPath == "" {
+		return fs.Sub(embeddedMappings, "mappings")
+	}
+	return os.DirFS(mappingsPath), nil
 }
 
-func loadMapping(providerMappingFolder string) error {
-	files, err := os.ReadDir(providerMappingFolder)
+func loadMapping(mappingsFS fs.FS, providerMappingFolder string, mappings *Mappings) error {
+	files, err := fs.ReadDir(mappingsFS, providerMappingFolder)
 	if err != nil {
 		return err
 	}
@@ -67,7 +132,7 @@ func loadMapping(providerMappingFolder string) error {
 		if file.IsDir() {
 			continue
 		}
-		yamlFile, err := os.ReadFile(filepath.Join(providerMappingFolder, file.Name()))
+		yamlFile, err := fs.ReadFile(mappingsFS, path.Join(providerMappingFolder, file.Name()))
 		if err != nil {
 			return err
 		}
@@ -91,8 +156,8 @@ func loadMapping(providerMappingFolder string) error {
 
 	}
 
-	maps.Copy(*globalMappings.General, *mergedMappings.General)
-	maps.Copy(*globalMappings.ComputeResource, *mergedMappings.ComputeResource)
+	maps.Copy(*mappings.General, *mergedMappings.General)
+	maps.Copy(*mappings.ComputeResource, *mergedMappings.ComputeResource)
 
 	return nil
 }
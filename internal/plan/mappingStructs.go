@@ -19,14 +19,16 @@ type DiskTypes struct {
 }
 
 type ResourceMapping struct {
-	Paths      []string                         `yaml:"paths"`
-	Type       string                           `yaml:"type"`
-	Variables  *ResourceMapping                 `yaml:"variables,omitempty"`
-	Properties *map[string][]PropertyDefinition `yaml:"properties"`
+	Paths        []string                         `yaml:"paths"`
+	IgnoredPaths []string                         `yaml:"ignored_paths,omitempty"` // estimated with another resource, not reported as unsupported
+	Type         string                           `yaml:"type"`
+	Variables    *ResourceMapping                 `yaml:"variables,omitempty"`
+	Properties   *map[string][]PropertyDefinition `yaml:"properties"`
 }
 
 type PropertyDefinition struct {
 	Paths     []string           `yaml:"paths"`
+	Type      *string            `yaml:"type,omitempty"` // "list" for lists of items, else informative
 	Unit      *string            `yaml:"unit,omitempty"`
 	Default   interface{}        `yaml:"default,omitempty"`
 	ValueType *string            `yaml:"value_type,omitempty"`
@@ -0,0 +1,111 @@
+package plan
+
+import (
+	"strings"
+
+	"github.com/carboniferio/carbonifer/internal/resources"
+)
+
+// newProvenance returns where a value resolved by a property mapping comes from, pathRaw being its path before
+// placeholders are resolved and key the value looked up in its reference, if any
+func newProvenance(propertyMapping *PropertyDefinition, pathRaw string, path string, regexApplied bool, key string, context *tfContext) *resources.Provenance {
+	provenance := resources.Provenance{
+		Source: resources.ProvenancePlan,
+		Path:   path,
+	}
+	referencedResource := ""
+	for _, match := range placeholderRegex.FindAllStringSubmatch(pathRaw, -1) {
+		expression := match[1]
+		if strings.HasPrefix(expression, "config.") {
+			provenance.Config = append(provenance.Config, strings.TrimPrefix(expression, "config."))
+		} else if referencedResource == "" && isReferencedResource(expression, context) {
+			referencedResource = expression
+		}
+	}
+	if regexApplied {
+		provenance.Regex = propertyMapping.Regex.Pattern
+	}
+
+	reference := propertyMapping.Reference
+	switch {
+	case reference != nil && (reference.JSONFile != "" || reference.General != ""):
+		provenance.Source = resources.ProvenanceDataFile
+		provenance.Reference = describeReference(reference, context)
+		provenance.Key = key
+	case reference != nil && reference.Paths != nil:
+		provenance.Source = resources.ProvenanceReference
+		provenance.Reference = describeReference(reference, context)
+	case referencedResource != "":
+		provenance.Source = resources.ProvenanceReference
+		provenance.Reference = referencedResource
+	case regexApplied:
+		provenance.Source = resources.ProvenanceRegex
+	case len(provenance.Config) > 0:
+		provenance.Source = resources.ProvenanceConfig
+	}
+	return &provenance
+}
+
+// newDefaultProvenance returns the provenance of the default value of a property mapping
+func newDefaultProvenance(propertyMapping *PropertyDefinition, context *tfContext) *resources.Provenance {
+	return &resources.Provenance{
+		Source:    resources.ProvenanceDefault,
+		Reference: describeReference(propertyMapping.Reference, context),
+	}
+}
+
+// newNotFoundProvenance returns the provenance of a property whose key was not found in the data file of its reference,
+// such as an unknown machine type
+func newNotFoundProvenance(propertyMapping *PropertyDefinition, path string, key string, context *tfContext) *resources.Provenance {
+	return &resources.Provenance{
+		Source:    resources.ProvenanceNotFound,
+		Path:      path,
+		Reference: describeReference(propertyMapping.Reference, context),
+		Key:       key,
+	}
+}
+
+// isReferencedResource returns true if a variable of the resource mapping is the path of another resource of the plan,
+// such as the launch template of an autoscaling group
+func isReferencedResource(variable string, context *tfContext) bool {
+	if context.RootContext == nil || context.RootContext.Mapping.Variables == nil || context.RootContext.Mapping.Variables.Properties == nil {
+		return false
+	}
+	for _, definition := range (*context.RootContext.Mapping.Variables.Properties)[variable] {
+		if definition.Reference != nil && definition.Reference.ReturnPath {
+			return true
+		}
+	}
+	return false
+}
+
+// recordProperty records the provenance of a property resolved by a property mapping, in the resource and in its
+// explanation if it is explained, with the raw and resolved values
+func recordProperty(key string, context *tfContext, propertyMapping *PropertyDefinition, provenance *resources.Provenance, rawValue interface{}, value interface{}) {
+	recordProvenance(key, context, provenance)
+	traceProperty(key, context, propertyMapping, provenance, rawValue, value)
+}
+
+// recordProvenance records the provenance of a property of the resource, only for its own properties, not the ones
+// of its variables or of the items of its lists. The first provenance of a property is kept.
+func recordProvenance(key string, context *tfContext, provenance *resources.Provenance) {
+	rootContext := context.RootContext
+	if rootContext == nil || provenance == nil || context.ResourceAddress != rootContext.ResourceAddress {
+		return
+	}
+	if rootContext.Provenance == nil {
+		rootContext.Provenance = map[string]resources.Provenance{}
+	}
+	if _, ok := rootContext.Provenance[key]; !ok {
+		rootContext.Provenance[key] = *provenance
+	}
+}
+
+// recordItemProvenance records the provenance of the properties of an item of a list of the resource, such as storage[0].size
+func recordItemProvenance(prefix string, item map[string]interface{}, context *tfContext) {
+	for key, value := range item {
+		if value, ok := value.(*valueWithUnit); ok && value != nil {
+			recordProvenance(prefix+"."+key, context.RootContext, value.Provenance)
+		}
+	}
+}
@@ -13,8 +13,9 @@ import (
 )
 
 type storage struct {
-	SizeGb decimal.Decimal
-	IsSSD  bool
+	SizeGb    decimal.Decimal
+	IsSSD     bool
+	IsArchive bool
 }
 
 func applyReference(valueFound string, propertyMapping *PropertyDefinition, context *tfContext) (interface{}, error) {
@@ -27,17 +28,21 @@ func applyReference(valueFound string, propertyMapping *PropertyDefinition, cont
 }
 
 func resolveReference(key string, reference *Reference, context *tfContext) (interface{}, error) {
-	generalMappings := (*globalMappings.General)[context.Provider]
+	plan := context.RootContext.Plan
+	generalMappings := (*plan.Mappings.General)[context.Provider]
 	if reference.JSONFile != "" {
 		filename, ok := (*generalMappings.JSONData)[reference.JSONFile]
 		if !ok {
-			log.Fatalf("Cannot find file %v in general.json_data", reference.JSONFile)
+			return nil, errors.Errorf("Cannot find file %v in general.json_data", reference.JSONFile)
+		}
+		byteValue, err := data.ReadDataFile(plan.Config.GetString("data.path"), filename.(string))
+		if err != nil {
+			return nil, err
 		}
-		byteValue := data.ReadDataFile(filename.(string))
 		var fileMap map[string]interface{}
-		err := json.Unmarshal([]byte(byteValue), &fileMap)
+		err = json.Unmarshal([]byte(byteValue), &fileMap)
 		if err != nil {
-			log.Fatal(err)
+			return nil, &data.MalformedDataError{File: filename.(string), Err: err}
 		}
 		item, ok := fileMap[key]
 		if !ok {
@@ -76,7 +81,7 @@ func resolveReference(key string, reference *Reference, context *tfContext) (int
 		}
 		return SSD, nil
 	}
-	if reference.Paths != nil {
+	if reference.Paths != nil && plan.TfPlan != nil {
 		templatePlaceholders := map[string]string{
 			"key": key,
 		}
@@ -85,7 +90,7 @@ func resolveReference(key string, reference *Reference, context *tfContext) (int
 			return nil, err
 		}
 		for _, path := range paths {
-			referencedItems, err := getJSON(path, *TfPlan)
+			referencedItems, err := getJSON(path, *plan.TfPlan, plan)
 			if err != nil {
 				errW := errors.Wrapf(err, "Cannot find referenced path in terraform plan: '%v'", path)
 				return nil, errW
@@ -148,6 +153,6 @@ func applyValidator(valueFound interface{}, propertyMapping *PropertyDefinition,
 }
 
 func resolveValidator(value interface{}, validator *string, context *tfContext) error {
-	_, err := getJSON(*validator, value)
+	_, err := getJSON(*validator, value, nil)
 	return errors.Wrapf(err, "Cannot validate '%v' value of %v", value, context.ResourceAddress)
 }
@@ -2,6 +2,7 @@ package plan
 
 import (
 	"fmt"
+	"math"
 	"regexp"
 	"strings"
 
@@ -10,22 +11,48 @@ import (
 	"github.com/carboniferio/carbonifer/internal/utils"
 	"github.com/pkg/errors"
 	"github.com/shopspring/decimal"
+	"github.com/spf13/viper"
 
 	log "github.com/sirupsen/logrus"
 )
 
-// TfPlan is the Terraform plan
-var TfPlan *map[string]interface{}
+// planContext is the state shared by the resources read from a plan
+type planContext struct {
+	Config      *viper.Viper            // Configuration of the estimation
+	TfPlan      *map[string]interface{} // Terraform plan, nil if a resource is read on its own
+	Mappings    *Mappings               // Mappings of the terraform resources
+	Assumptions *Assumptions            // Assumptions file of the estimation, loaded once
+	Explanation *Explanation            // Explanation of a resource being recorded by ExplainResource, nil otherwise
+}
 
-// GetResources returns the resources of the Terraform plan
-func GetResources(tfplan *map[string]interface{}) (map[string]resources.Resource, error) {
-	TfPlan = tfplan
+// GetResources returns the resources of the Terraform plan, with the assumptions file set in config
+func GetResources(cfg *viper.Viper, tfplan *map[string]interface{}) (map[string]resources.Resource, error) {
+	assumptions, err := LoadAssumptions(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return GetResourcesWithAssumptions(cfg, tfplan, assumptions)
+}
+
+// GetResourcesWithAssumptions returns the resources of the Terraform plan, with assumptions already loaded
+func GetResourcesWithAssumptions(cfg *viper.Viper, tfplan *map[string]interface{}, assumptions *Assumptions) (map[string]resources.Resource, error) {
+	return getResources(tfplan, &planContext{Config: cfg, Assumptions: assumptions})
+}
+
+func getResources(tfplan *map[string]interface{}, plan *planContext) (map[string]resources.Resource, error) {
+	mapping, err := GetMapping(plan.Config)
+	if err != nil {
+		errW := errors.Wrap(err, "Cannot get mapping")
+		return nil, errW
+	}
+	plan.TfPlan = tfplan
+	plan.Mappings = mapping
 
 	plannedResources := []interface{}{}
 
 	// Get resources from Terraform plan
-	jqPath := ".planned_values | .. | objects | select(has(\"resources\")) | .resources[]"
-	plannedResourcesResult, err := utils.GetJSON(jqPath, *TfPlan)
+	jqPath := ".planned_values.root_module | recurse(.child_modules[]?) | .resources[]?"
+	plannedResourcesResult, err := utils.GetJSON(jqPath, *tfplan)
 
 	if err != nil {
 		return nil, err
@@ -42,13 +69,21 @@ func GetResources(tfplan *map[string]interface{}) (map[string]resources.Resource
 
 	// Get compute resources
 	resourcesMap := map[string]resources.Resource{}
-	mapping, err := GetMapping()
-	if err != nil {
-		errW := errors.Wrap(err, "Cannot get mapping")
-		return nil, errW
-	}
+	ignoredAddresses := map[string]bool{}
 	for resourceType, mapping := range *mapping.ComputeResource {
-		resources, err := getResourcesOfType(resourceType, &mapping)
+		for _, path := range mapping.IgnoredPaths {
+			ignoredResources, err := getJSON(path, *tfplan, plan)
+			if err != nil {
+				return nil, errors.Wrapf(err, "Cannot find ignored resources of type %v for path %v", resourceType, path)
+			}
+			for _, ignoredResourceI := range ignoredResources {
+				if ignoredResource, ok := ignoredResourceI.(map[string]interface{}); ok {
+					resourceAddress, _ := ignoredResource["address"].(string)
+					ignoredAddresses[resourceAddress] = true
+				}
+			}
+		}
+		resources, err := getResourcesOfType(resourceType, &mapping, plan)
 		if err != nil {
 			errW := errors.Wrapf(err, "Cannot get resources of type %v", resourceType)
 			return nil, errW
@@ -72,7 +107,7 @@ func GetResources(tfplan *map[string]interface{}) (map[string]resources.Resource
 		if resourceMap == nil {
 			// That is an unsupported resource
 			resourceType := resource["type"].(string)
-			if checkIgnoredResource(resourceType, provider) {
+			if checkIgnoredResource(resourceType, provider, plan.Mappings) || ignoredAddresses[resourceAddress] {
 				continue
 			}
 			unsupportedResource := resources.UnsupportedResource{
@@ -91,8 +126,8 @@ func GetResources(tfplan *map[string]interface{}) (map[string]resources.Resource
 	return resourcesMap, nil
 }
 
-func checkIgnoredResource(resourceType string, provider providers.Provider) bool {
-	ignoredResourceNames := (*globalMappings.General)[provider].IgnoredResources
+func checkIgnoredResource(resourceType string, provider providers.Provider, mappings *Mappings) bool {
+	ignoredResourceNames := (*mappings.General)[provider].IgnoredResources
 	if ignoredResourceNames != nil {
 		for _, ignoredResource := range *ignoredResourceNames {
 			if ignoredResource == resourceType {
@@ -109,7 +144,7 @@ func checkIgnoredResource(resourceType string, provider providers.Provider) bool
 	}
 	return false
 }
-func getResourcesOfType(resourceType string, mapping *ResourceMapping) ([]resources.Resource, error) {
+func getResourcesOfType(resourceType string, mapping *ResourceMapping, plan *planContext) ([]resources.Resource, error) {
 	pathsProperty := mapping.Paths
 	paths, err := readPaths(pathsProperty)
 	if err != nil {
@@ -120,17 +155,28 @@ func getResourcesOfType(resourceType string, mapping *ResourceMapping) ([]resour
 	resourcesResult := []resources.Resource{}
 	for _, path := range paths {
 		log.Debugf("  Reading resources of type '%s' from path '%s'", resourceType, path)
-		resourcesFound, err := getJSON(path, *TfPlan)
+		resourcesFound, err := getJSON(path, *plan.TfPlan, plan)
 		if err != nil {
 			errW := errors.Wrapf(err, "Cannot find resource for path %v", path)
 			return nil, errW
 		}
 		log.Debugf("  Found %d resources of type '%s'", len(resourcesFound), resourceType)
 		for _, resourceI := range resourcesFound {
-			resourcesResultGot, err := GetComputeResource(resourceI, mapping, resourcesResult)
+			if resource, ok := resourceI.(map[string]interface{}); ok {
+				resourceAddress, _ := resource["address"].(string)
+				traceMapping(resourceAddress, resourceType, path, plan)
+			}
+			resourcesResultGot, err := getComputeResource(resourceI, mapping, resourcesResult, plan)
 			if err != nil {
-				errW := errors.Wrapf(err, "Cannot get compute resource for path %v", path)
-				return nil, errW
+				failedResource, ok := getFailedResource(resourceI, resourceType, err)
+				if !ok {
+					errW := errors.Wrapf(err, "Cannot get compute resource for path %v", path)
+					return nil, errW
+				}
+				// Keep the other resources, this one being reported with the reason it failed
+				log.Warnf("Cannot get compute resource %v: %v", failedResource.GetAddress(), err)
+				resourcesResult = append(resourcesResult, failedResource)
+				continue
 			}
 			if resourcesResultGot != nil {
 				resourcesResult = resourcesResultGot
@@ -142,7 +188,44 @@ func getResourcesOfType(resourceType string, mapping *ResourceMapping) ([]resour
 
 }
 
-func GetComputeResource(resourceI interface{}, resourceMapping *ResourceMapping, resourcesResult []resources.Resource) ([]resources.Resource, error) {
+// getFailedResource returns the resource of the plan that could not be read, with the error
+func getFailedResource(resourceI interface{}, resourceType string, err error) (resources.FailedResource, bool) {
+	resource, ok := resourceI.(map[string]interface{})
+	if !ok {
+		return resources.FailedResource{}, false
+	}
+	resourceAddress, ok := resource["address"].(string)
+	if !ok {
+		return resources.FailedResource{}, false
+	}
+	providerName, _ := resource["provider_name"].(string)
+	provider, perr := parseProvider(providerName)
+	if perr != nil {
+		return resources.FailedResource{}, false
+	}
+	name, _ := resource["name"].(string)
+	return resources.FailedResource{
+		Identification: &resources.ResourceIdentification{
+			Address:      resourceAddress,
+			Name:         name,
+			ResourceType: resourceType,
+			Provider:     provider,
+			Count:        1,
+		},
+		Err: err,
+	}, true
+}
+
+// GetComputeResource appends to resourcesResult the compute resource read from a resource of the plan, without assumptions
+func GetComputeResource(cfg *viper.Viper, resourceI interface{}, resourceMapping *ResourceMapping, resourcesResult []resources.Resource) ([]resources.Resource, error) {
+	mappings, err := GetMapping(cfg)
+	if err != nil {
+		return nil, errors.Wrap(err, "Cannot get mapping")
+	}
+	return getComputeResource(resourceI, resourceMapping, resourcesResult, &planContext{Config: cfg, Mappings: mappings})
+}
+
+func getComputeResource(resourceI interface{}, resourceMapping *ResourceMapping, resourcesResult []resources.Resource, plan *planContext) ([]resources.Resource, error) {
 	resource := resourceI.(map[string]interface{})
 	resourceAddress := resource["address"].(string)
 	providerName, ok := resource["provider_name"].(string)
@@ -153,11 +236,15 @@ func GetComputeResource(resourceI interface{}, resourceMapping *ResourceMapping,
 	if err != nil {
 		return nil, nil
 	}
+	resourceType, _ := resource["type"].(string)
+	resourceAssumptions := plan.Assumptions.resourceAssumptions(resourceType, resourceAddress)
 	contextObject := tfContext{
 		ResourceAddress: resourceAddress,
 		Mapping:         resourceMapping,
 		Resource:        resource,
 		Provider:        provider,
+		Plan:            plan,
+		Assumptions:     resourceAssumptions,
 	}
 	contextObject.RootContext = &contextObject
 	context := &contextObject
@@ -174,23 +261,28 @@ func GetComputeResource(resourceI interface{}, resourceMapping *ResourceMapping,
 		if region == nil {
 			return nil, errors.Errorf("Cannot find default region for resource %v", resourceAddress)
 		}
+		recordProvenance("region", context, &resources.Provenance{Source: resources.ProvenanceDefault, Reference: "AWS default region"})
 	}
 
-	resourceType, err := getString("type", context)
+	mappedResourceType, err := getString("type", context)
 	if err != nil {
 		return nil, errors.Wrapf(err, "Cannot get type for resource %v", resourceAddress)
 	}
 
-	index := resource["index"]
-	if index != nil {
-		nameStr := fmt.Sprintf("%s[%d]", *name, int(index.(float64)))
+	switch index := resource["index"].(type) {
+	case float64:
+		nameStr := fmt.Sprintf("%s[%d]", *name, int(index))
+		name = &nameStr
+	case string:
+		// Key of for_each
+		nameStr := fmt.Sprintf("%s[%q]", *name, index)
 		name = &nameStr
 	}
 
 	computeResource := resources.ComputeResource{
 		Identification: &resources.ResourceIdentification{
 			Name:         *name,
-			ResourceType: *resourceType,
+			ResourceType: *mappedResourceType,
 			Provider:     provider,
 			Region:       *region,
 			Address:      resourceAddress,
@@ -208,11 +300,16 @@ func GetComputeResource(resourceI interface{}, resourceMapping *ResourceMapping,
 	}
 	if vcpus != nil && vcpus.Value != nil {
 
-		intValue, err := utils.ParseToInt(vcpus.Value)
+		decimalValue, err := utils.ParseToDecimal(vcpus.Value)
 		if err != nil {
 			return nil, errors.Wrapf(err, "Cannot parse vCPUs for %v", resourceAddress)
 		}
-		computeResource.Specs.VCPUs = int32(intValue)
+		if decimalValue.IsInteger() {
+			computeResource.Specs.VCPUs = int32(decimalValue.IntPart())
+		} else {
+			// Serverless resources can have a fraction of vCPU
+			computeResource.Specs.FractionalVCPUs = decimalValue
+		}
 
 	}
 
@@ -242,22 +339,27 @@ func GetComputeResource(resourceI interface{}, resourceMapping *ResourceMapping,
 		case "b":
 			computeResource.Specs.MemoryMb /= 1024 * 1024
 		default:
-			log.Fatalf("Unknown unit for memory: %v", unit)
+			return nil, errors.Errorf("Unknown unit for memory of %v: %v", resourceAddress, unit)
 		}
 	}
+	err = checkUnknownInstanceType(&computeResource, context)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Cannot get vCPUs for %v", resourceAddress)
+	}
 
 	// Add GPUs
 	gpus, err := getSlice("guest_accelerator", context)
 	if err != nil {
 		return nil, errors.Wrapf(err, "Cannot get GPUs for %v", resourceAddress)
 	}
-	for _, gpuI := range gpus {
+	for i, gpuI := range gpus {
 		gpu := gpuI.(map[string]interface{})
 		gpuTypes, err := getGPU(gpu)
 		if err != nil {
 			return nil, errors.Wrapf(err, "Cannot get GPU types for %v", resourceAddress)
 		}
 		computeResource.Specs.GpuTypes = append(computeResource.Specs.GpuTypes, gpuTypes...)
+		recordItemProvenance(fmt.Sprintf("guest_accelerator[%v]", i), gpu, context)
 	}
 
 	// Add CPU type
@@ -269,6 +371,39 @@ func GetComputeResource(resourceI interface{}, resourceMapping *ResourceMapping,
 		computeResource.Specs.CPUType = *cpuType
 	}
 
+	// Add CPU platforms of the machine type, only used by CPU platform policies other than flat
+	cpuTypes, err := getCPUTypes(context)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Cannot get CPU platforms for %v", resourceAddress)
+	}
+	if cpuTypes != nil {
+		cpuTypesSlice, ok := cpuTypes.Value.([]interface{})
+		if !ok {
+			return nil, errors.Errorf("Cannot convert CPU platforms of %v to a list: %v", resourceAddress, cpuTypes.Value)
+		}
+		for _, cpuTypeI := range cpuTypesSlice {
+			computeResource.Specs.CPUTypes = append(computeResource.Specs.CPUTypes, fmt.Sprint(cpuTypeI))
+		}
+	}
+
+	// Add utilization
+	avgCPUUse, source, err := getUtilization("avg_cpu_use", resourceAssumptions.AvgCPUUse, context)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Cannot get CPU utilization for %v", resourceAddress)
+	}
+	if avgCPUUse != nil {
+		computeResource.Specs.AvgCPUUse = *avgCPUUse
+		computeResource.Specs.AvgCPUUseSource = source
+	}
+	avgGPUUse, source, err := getUtilization("avg_gpu_use", resourceAssumptions.AvgGPUUse, context)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Cannot get GPU utilization for %v", resourceAddress)
+	}
+	if avgGPUUse != nil {
+		computeResource.Specs.AvgGPUUse = *avgGPUUse
+		computeResource.Specs.AvgGPUUseSource = source
+	}
+
 	// Add replication factor
 	replicationFactor, err := getValue("replication_factor", context)
 	if err != nil {
@@ -295,10 +430,61 @@ func GetComputeResource(resourceI interface{}, resourceMapping *ResourceMapping,
 			return nil, errors.Wrapf(err, "Cannot parse count for %v", resourceAddress)
 		}
 		computeResource.Identification.Count = int64(intValue)
+		countLow, countHigh, err := getCountRange(int64(intValue), context)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Cannot get count range for %v", resourceAddress)
+		}
+		if countLow != computeResource.Identification.Count || countHigh != computeResource.Identification.Count {
+			computeResource.Identification.CountLow = countLow
+			computeResource.Identification.CountHigh = countHigh
+		}
 	} else {
 		computeResource.Identification.Count = 1
 	}
 
+	// Add usage ratio (case of serverless resources)
+	usageRatio, err := getValue("usage_ratio", context)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Cannot get usage ratio for %v", resourceAddress)
+	}
+	if usageRatio != nil && usageRatio.Value != nil {
+		decimalValue, err := utils.ParseToDecimal(usageRatio.Value)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Cannot parse usage ratio for %v", resourceAddress)
+		}
+		computeResource.Identification.UsageRatio = decimalValue
+	}
+
+	// Add operating schedule (resources stopped at some hours)
+	runningRatio, err := getRunningRatio(resourceAssumptions, context)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Cannot get schedule for %v", resourceAddress)
+	}
+	if runningRatio != nil && runningRatio.IsZero() {
+		// A usage ratio of 0 means always running, no instance is running instead
+		log.Debugf("%v is never running", resourceAddress)
+		computeResource.Identification.Count = 0
+	} else if runningRatio != nil {
+		if computeResource.Identification.UsageRatio.IsZero() {
+			computeResource.Identification.UsageRatio = *runningRatio
+		} else {
+			computeResource.Identification.UsageRatio = computeResource.Identification.UsageRatio.Mul(*runningRatio)
+		}
+	}
+
+	if resourceAssumptions.Spot != nil {
+		computeResource.Identification.Spot = *resourceAssumptions.Spot
+	}
+
+	// Add network egress
+	networkEgress, err := getNetworkEgress(context)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Cannot get network egress for %v", resourceAddress)
+	}
+	if networkEgress != nil {
+		computeResource.Specs.NetworkEgressGb = *networkEgress
+	}
+
 	// Add storage
 	storages, err := getSlice("storage", context)
 	if err != nil {
@@ -313,19 +499,210 @@ func GetComputeResource(resourceI interface{}, resourceMapping *ResourceMapping,
 		if err != nil {
 			return nil, errors.Wrapf(err, "Cannot get storage[%v] for %v", i, resourceAddress)
 		}
+		recordItemProvenance(fmt.Sprintf("storage[%v]", i), storageI.(map[string]interface{}), context)
 		size := storage.SizeGb
 		if storage.IsSSD {
 			computeResource.Specs.SsdStorage = computeResource.Specs.SsdStorage.Add(size)
+		} else if storage.IsArchive {
+			computeResource.Specs.ArchiveStorage = computeResource.Specs.ArchiveStorage.Add(size)
 		} else {
 			computeResource.Specs.HddStorage = computeResource.Specs.HddStorage.Add(size)
 		}
 	}
+	if resourceAssumptions.StorageFill != nil {
+		// Only the used part of the storage is estimated
+		storageFill := decimal.NewFromFloat(*resourceAssumptions.StorageFill)
+		computeResource.Specs.HddStorage = computeResource.Specs.HddStorage.Mul(storageFill)
+		computeResource.Specs.SsdStorage = computeResource.Specs.SsdStorage.Mul(storageFill)
+		if !computeResource.Specs.ArchiveStorage.IsZero() {
+			computeResource.Specs.ArchiveStorage = computeResource.Specs.ArchiveStorage.Mul(storageFill)
+		}
+	}
+
+	computeResource.Specs.Provenance = context.Provenance
 
 	resourcesResult = append(resourcesResult, computeResource)
 	log.Debugf("    Reading resource '%s'", computeResource.GetAddress())
 	return resourcesResult, nil
 }
 
+// getNetworkEgress returns the monthly data transfer declared in the mapping, or else in the tags (AWS) or labels (GCP) of the resource
+func getNetworkEgress(context *tfContext) (*decimal.Decimal, error) {
+	networkEgress, err := getValue("network_egress", context)
+	if err != nil {
+		return nil, err
+	}
+	if networkEgress == nil || networkEgress.Value == nil {
+		tagValue, source, err := getTagValue("monthly_egress_gb", context)
+		if err != nil {
+			return nil, err
+		}
+		if tagValue == nil {
+			return nil, nil
+		}
+		networkEgress = &valueWithUnit{
+			Value:      tagValue,
+			Provenance: &resources.Provenance{Source: resources.ProvenancePlan, Reference: source},
+		}
+		recordProvenance("network_egress", context, networkEgress.Provenance)
+	}
+	egressGb, err := utils.ParseToDecimal(networkEgress.Value)
+	if err != nil {
+		return nil, err
+	}
+	if networkEgress.Unit != nil && strings.ToLower(*networkEgress.Unit) == "tb" {
+		egressGb = egressGb.Mul(decimal.NewFromInt32(1024))
+	}
+	return &egressGb, nil
+}
+
+// getTagValue returns the value of the tag `carbonifer/<name>` (AWS) or the label `carbonifer_<name>` (GCP) of the resource, and its source
+func getTagValue(name string, context *tfContext) (interface{}, string, error) {
+	var tagPath, source string
+	switch context.Provider {
+	case providers.AWS:
+		tagPath = fmt.Sprintf(`.values.tags["carbonifer/%s"]?`, name)
+		source = resources.SourceTag
+	case providers.GCP:
+		tagPath = fmt.Sprintf(`.values.labels["carbonifer_%s"]?`, name)
+		source = resources.SourceLabel
+	default:
+		return nil, "", nil
+	}
+	values, err := utils.GetJSON(tagPath, context.Resource)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(values) == 0 || values[0] == nil {
+		return nil, "", nil
+	}
+	return values[0], source, nil
+}
+
+// getUtilization returns the utilization of the resource set in the assumptions file, or else in its tags or labels.
+// A value greater than 1 is a percentage, as GCP labels cannot contain dots.
+func getUtilization(name string, overrideValue *float64, context *tfContext) (*decimal.Decimal, string, error) {
+	if overrideValue != nil {
+		utilization := decimal.NewFromFloat(*overrideValue)
+		return &utilization, resources.SourceAssumptions, checkUtilization(name, utilization)
+	}
+	tagValue, source, err := getTagValue(name, context)
+	if err != nil || tagValue == nil {
+		return nil, "", err
+	}
+	utilization, err := utils.ParseToDecimal(tagValue)
+	if err != nil {
+		return nil, "", err
+	}
+	if utilization.GreaterThan(decimal.NewFromInt(1)) {
+		utilization = utilization.Shift(-2)
+	}
+	return &utilization, source, checkUtilization(name, utilization)
+}
+
+// getCountRange returns the lowest and highest counts of the resource when the average autoscaler size varies within
+// `uncertainty.avg_autoscaler_size_percent`, the same count if it does not depend on the autoscaler
+func getCountRange(count int64, context *tfContext) (int64, int64, error) {
+	cfg := context.RootContext.Plan.Config
+	delta := cfg.GetFloat64("uncertainty.avg_autoscaler_size_percent")
+	if delta == 0 {
+		return count, count, nil
+	}
+	rootContext := context.RootContext
+	resourceAssumptions := rootContext.Assumptions
+	defer func() {
+		rootContext.Assumptions = resourceAssumptions
+	}()
+
+	percent := cfg.GetFloat64(fmt.Sprintf("provider.%v.avg_autoscaler_size_percent", strings.ToLower(context.Provider.String())))
+	if resourceAssumptions.AvgAutoscalerSizePercent != nil {
+		percent = *resourceAssumptions.AvgAutoscalerSizePercent
+	}
+
+	counts := []int64{}
+	for _, variedPercent := range []float64{math.Max(percent-delta, 0), math.Min(percent+delta, 1)} {
+		variedPercent := variedPercent
+		variedAssumptions := *resourceAssumptions
+		variedAssumptions.AvgAutoscalerSizePercent = &variedPercent
+		rootContext.Assumptions = &variedAssumptions
+		variedCount, err := getValue("count", context)
+		if err != nil {
+			return 0, 0, err
+		}
+		if variedCount == nil || variedCount.Value == nil {
+			return count, count, nil
+		}
+		intValue, err := utils.ParseToInt(variedCount.Value)
+		if err != nil {
+			return 0, 0, err
+		}
+		counts = append(counts, int64(intValue))
+	}
+	if counts[0] > counts[1] {
+		return counts[1], counts[0], nil
+	}
+	return counts[0], counts[1], nil
+}
+
+// getRunningRatio returns the share of time the resource is running, from the schedule of the assumptions file,
+// or else from the schedules of the plan, or else from the hours per day of the assumptions file. Nil if always running.
+func getRunningRatio(resourceAssumptions *ResourceAssumptions, context *tfContext) (*decimal.Decimal, error) {
+	startCrons := []string{}
+	stopCrons := []string{}
+	if resourceAssumptions.Schedule != nil {
+		startCrons = append(startCrons, resourceAssumptions.Schedule.Start)
+		stopCrons = append(stopCrons, resourceAssumptions.Schedule.Stop)
+	} else {
+		schedules, err := getSlice("schedule", context)
+		if err != nil {
+			return nil, err
+		}
+		for _, scheduleI := range schedules {
+			schedule, ok := scheduleI.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			start, ok := schedule["start"].(*valueWithUnit)
+			if ok && start != nil && start.Value != nil {
+				startCrons = append(startCrons, fmt.Sprintf("%v", start.Value))
+			}
+			stop, ok := schedule["stop"].(*valueWithUnit)
+			if ok && stop != nil && stop.Value != nil {
+				stopCrons = append(stopCrons, fmt.Sprintf("%v", stop.Value))
+			}
+		}
+	}
+	if len(startCrons) > 0 || len(stopCrons) > 0 {
+		runningRatio, err := utils.WeeklyRunningRatio(startCrons, stopCrons)
+		if err == nil {
+			return &runningRatio, nil
+		}
+		// A schedule with only stops (started manually) or only starts cannot be estimated
+		log.Warnf("Ignoring schedule of %v: %v", context.ResourceAddress, err)
+	}
+	if resourceAssumptions.HoursPerDay != nil && *resourceAssumptions.HoursPerDay < 24 {
+		hoursRatio := decimal.NewFromFloat(*resourceAssumptions.HoursPerDay).Div(decimal.NewFromInt(24))
+		return &hoursRatio, nil
+	}
+	return nil, nil
+}
+
+func checkUtilization(name string, utilization decimal.Decimal) error {
+	if utilization.IsNegative() || utilization.GreaterThan(decimal.NewFromInt(1)) {
+		return errors.Errorf("%v must be between 0 and 1 (or 0 and 100 percent): %v", name, utilization)
+	}
+	return nil
+}
+
+// getCPUTypes returns the CPU platforms of the machine type of the resource, nil if the CPU platform policy is flat
+func getCPUTypes(context *tfContext) (*valueWithUnit, error) {
+	policy := context.RootContext.Plan.Config.GetString(fmt.Sprintf("provider.%v.cpu_platform_policy", strings.ToLower(context.Provider.String())))
+	if policy == "" || policy == "flat" {
+		return nil, nil
+	}
+	return getValue("cpu_types", context)
+}
+
 func getGPU(gpu map[string]interface{}) ([]string, error) {
 	gpuTypes := []string{}
 	gpuType := gpu["type"].(*valueWithUnit)
@@ -362,7 +739,7 @@ func getStorage(storageMap map[string]interface{}) (*storage, error) {
 	}
 	storageSizeGb, err := decimal.NewFromString(fmt.Sprintf("%v", storageSize.Value))
 	if err != nil {
-		log.Fatal(err)
+		return nil, errors.Wrapf(err, "Cannot parse storage size '%v'", storageSize.Value)
 	}
 	storageType := storageMap["type"].(*valueWithUnit)
 	// TODO get storage size unit correctly
@@ -383,6 +760,7 @@ func getStorage(storageMap map[string]interface{}) (*storage, error) {
 	}
 
 	isSSD := false
+	isArchive := false
 	if storageType != nil {
 		diskType, ok := storageType.Value.(*DiskType)
 		if !ok {
@@ -396,13 +774,13 @@ func getStorage(storageMap map[string]interface{}) (*storage, error) {
 			}
 			diskType = &diskTypeParsed
 		}
-		if *diskType == SSD {
-			isSSD = true
-		}
+		isSSD = *diskType == SSD
+		isArchive = *diskType == ARCHIVE
 	}
 	storage := storage{
-		SizeGb: storageSizeGb,
-		IsSSD:  isSSD,
+		SizeGb:    storageSizeGb,
+		IsSSD:     isSSD,
+		IsArchive: isArchive,
 	}
 	return &storage, nil
 }
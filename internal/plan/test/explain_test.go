@@ -0,0 +1,52 @@
+package plan_test
+
+import (
+	"testing"
+
+	"github.com/carboniferio/carbonifer/internal/plan"
+	"github.com/carboniferio/carbonifer/internal/resources"
+	"github.com/carboniferio/carbonifer/internal/terraform"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExplainResource(t *testing.T) {
+	tfPlan, err := terraform.CarboniferPlan("test/terraform/planJson/plan.json")
+	assert.NoError(t, err)
+
+	explanation, err := plan.ExplainResource(viper.GetViper(), tfPlan, "google_compute_instance.default[0]")
+	assert.NoError(t, err)
+	assert.Equal(t, "google_compute_instance", explanation.MappingType)
+	assert.Contains(t, explanation.MappingPath, "google_compute_instance")
+	assert.IsType(t, resources.ComputeResource{}, explanation.Resource)
+
+	properties := map[string]plan.PropertyTrace{}
+	for _, property := range explanation.Properties {
+		properties[property.Property] = property
+	}
+
+	vCPUs := properties["vCPUs"]
+	assert.Equal(t, ".values.machine_type", vCPUs.Path)
+	assert.Equal(t, "n1-standard-2", vCPUs.RawValue)
+	assert.Equal(t, "gcp_instances.json (.vcpus)", vCPUs.Reference)
+	assert.EqualValues(t, 2, vCPUs.Value)
+
+	assert.Equal(t, "mb", properties["memory"].Unit)
+	assert.Equal(t, "nvidia-tesla-k80", properties["guest_accelerator.type"].Value)
+	assert.Equal(t, "gb", properties["storage.size"].Unit)
+	assert.Equal(t, resources.ProvenanceDefault, properties["replication_factor"].Source)
+
+	// Not found, the estimation using its default
+	count, ok := properties["count"]
+	assert.True(t, ok)
+	assert.Empty(t, count.Path)
+	assert.Nil(t, count.Value)
+}
+
+func TestExplainResource_NotFound(t *testing.T) {
+	tfPlan, err := terraform.CarboniferPlan("test/terraform/planJson/plan.json")
+	assert.NoError(t, err)
+
+	_, err = plan.ExplainResource(viper.GetViper(), tfPlan, "google_compute_instance.unknown")
+	assert.Error(t, err)
+}
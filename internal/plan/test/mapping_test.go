@@ -0,0 +1,101 @@
+package plan_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/carboniferio/carbonifer/internal/plan"
+	"github.com/carboniferio/carbonifer/internal/providers"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResourceTypes(t *testing.T) {
+	supported, ignored, err := plan.ResourceTypes(viper.GetViper())
+	assert.NoError(t, err)
+
+	assert.Contains(t, supported[providers.GCP], "google_compute_instance")
+	assert.Contains(t, supported[providers.AWS], "aws_instance")
+	assert.NotContains(t, supported[providers.GCP], "aws_instance")
+	assert.IsNonDecreasing(t, supported[providers.GCP])
+	assert.IsNonDecreasing(t, supported[providers.AWS])
+
+	assert.Contains(t, ignored[providers.GCP], "google_compute_autoscaler")
+	assert.NotEmpty(t, ignored[providers.AWS])
+}
+
+func TestValidateMappings(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.Mkdir(filepath.Join(dir, "aws"), 0o755))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "aws", "general.yaml"), []byte(`general:
+  aws:
+    json_data:
+      aws_instances: "aws_instances.json"
+`), 0o644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "aws", "instance.yaml"), []byte(`compute_resource:
+  aws_instance:
+    paths: cbf::all_select("type";  "aws_instance")
+    type: resource
+    variables:
+      properties:
+        ami:
+          - paths: '.configuration.root_module.resources[] | select(.address == "${this.address}")'
+    properties:
+      vCPUs:
+        - paths: ".values.instance_type"
+          reference:
+            json_file: aws_instances
+            property: ".VCPU"
+            zone:
+      memory:
+        - paths: ".values.instance_type"
+          unit: mib
+          reference:
+            json_file: aws_types
+            property: ".MemoryMb"
+      region:
+        - paths: ".values.availability_zone"
+          regex:
+            pattern: '^(.+-\d+'
+            group: 1
+      count:
+        - paths: '.values | select(.name", 1)'
+      storage:
+        - paths: '${ami}.values.root_block_device[0].volume_size * ${config.provider.aws.unknown}'
+        - paths: '${unknown}.values.volume_size'
+`), 0o644))
+
+	issues, err := plan.ValidateMappings(viper.GetViper(), dir)
+	assert.NoError(t, err)
+	messages := []string{}
+	for _, issue := range issues {
+		assert.Equal(t, "aws/instance.yaml", issue.File)
+		messages = append(messages, fmt.Sprintf("%v: %v", issue.Line, issue.Message))
+	}
+	assert.Contains(t, messages, "15: unknown key 'zone' in Reference")
+	assert.Contains(t, messages, "18: unknown unit 'mib', must be one of b, kb, mb, gb, tb, pb")
+	assert.Contains(t, messages, "20: json file 'aws_types' not found in general.json_data of provider AWS")
+	assert.Contains(t, messages, "25: invalid regular expression: error parsing regexp: missing closing ): `^(.+-\\d+`")
+	assert.Contains(t, messages, "30: unknown config key in placeholder ${config.provider.aws.unknown}")
+	assert.Contains(t, messages, "31: unknown variable in placeholder ${unknown}")
+	assert.Len(t, messages, 7)
+	for _, issue := range issues {
+		if issue.Line == 28 {
+			assert.Equal(t, "compute_resource.aws_instance.properties.count[0].paths[0]", issue.Location)
+			assert.Contains(t, issue.Message, "invalid jq query")
+		}
+	}
+}
+
+func TestValidateMappings_Embedded(t *testing.T) {
+	issues, err := plan.ValidateMappings(viper.GetViper(), "")
+	assert.NoError(t, err)
+	assert.Empty(t, issues)
+}
+
+func TestValidateMappings_NotADirectory(t *testing.T) {
+	_, err := plan.ValidateMappings(viper.GetViper(), "mapping_test.go")
+	assert.Error(t, err)
+}
@@ -0,0 +1,57 @@
+package plan_test
+
+import (
+	"testing"
+
+	"github.com/carboniferio/carbonifer/internal/plan"
+	"github.com/carboniferio/carbonifer/internal/resources"
+	"github.com/carboniferio/carbonifer/internal/terraform"
+	"github.com/shopspring/decimal"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetResource_Assumptions(t *testing.T) {
+
+	viper.Set("assumptions_file", "test/config/assumptions_storage.yaml")
+	defer viper.Set("assumptions_file", "")
+
+	tfPlan, err := terraform.CarboniferPlan("test/terraform/planJson/object_storage.json")
+	assert.NoError(t, err)
+	assumptions, err := plan.LoadAssumptions(viper.GetViper())
+	assert.NoError(t, err)
+	gotResources, err := plan.GetResourcesWithAssumptions(viper.GetViper(), tfPlan, assumptions)
+	assert.NoError(t, err)
+
+	// Storage fill of an address pattern
+	logs := gotResources["aws_s3_bucket.logs"].(resources.ComputeResource)
+	assert.Equal(t, decimal.NewFromInt(250).String(), logs.Specs.HddStorage.String())
+	replica := gotResources["aws_s3_bucket.replica"].(resources.ComputeResource)
+	assert.Equal(t, decimal.Zero.String(), replica.Specs.HddStorage.String())
+
+	// Config default of a resource type
+	dual := gotResources["google_storage_bucket.dual"].(resources.ComputeResource)
+	assert.Equal(t, decimal.NewFromInt(10).String(), dual.Specs.HddStorage.String())
+
+	assert.Equal(t, []string{"resources.aws_instance.*"}, assumptions.UnmatchedResources(gotResources))
+}
+
+func TestGetResource_AssumptionsInvalid(t *testing.T) {
+
+	viper.Set("assumptions_file", "test/config/assumptions_invalid.yaml")
+	defer viper.Set("assumptions_file", "")
+
+	tfPlan, err := terraform.CarboniferPlan("test/terraform/planJson/object_storage.json")
+	assert.NoError(t, err)
+	_, err = plan.GetResources(viper.GetViper(), tfPlan)
+	assert.ErrorContains(t, err, "avg_cpu_usage")
+}
+
+func TestGetResource_AssumptionsSpotInvalid(t *testing.T) {
+
+	viper.Set("assumptions_file", "test/config/assumptions_spot_invalid.yaml")
+	defer viper.Set("assumptions_file", "")
+
+	_, err := plan.LoadAssumptions(viper.GetViper())
+	assert.ErrorContains(t, err, "'google_storage_bucket': spot applies to instances only")
+}
@@ -33,6 +33,8 @@ func TestGetResource_AWSASG(t *testing.T) {
 				Provider:          providers.AWS,
 				Region:            "eu-west-3",
 				Count:             6,
+				CountLow:          4,
+				CountHigh:         8,
 				ReplicationFactor: 1,
 			},
 			Specs: &resources.ComputeResourceSpecs{
@@ -51,6 +53,8 @@ func TestGetResource_AWSASG(t *testing.T) {
 				Provider:          providers.AWS,
 				Region:            "eu-west-3",
 				Count:             6,
+				CountLow:          4,
+				CountHigh:         8,
 				ReplicationFactor: 1,
 			},
 			Specs: &resources.ComputeResourceSpecs{
@@ -64,14 +68,14 @@ func TestGetResource_AWSASG(t *testing.T) {
 	}
 	tfPlan, err := terraform.TerraformPlan()
 	assert.NoError(t, err)
-	gotResources, err := plan.GetResources(tfPlan)
+	gotResources, err := plan.GetResources(viper.GetViper(), tfPlan)
 	assert.NoError(t, err)
 	for _, got := range gotResources {
 		if got.GetIdentification().ResourceType == "aws_launch_configuration" {
 			// This should not exists, it should be ignored
 			assert.Fail(t, "aws_launch_configuration should be ignored")
 		} else if got.GetIdentification().ResourceType == "aws_autoscaling_group" {
-			assert.Equal(t, wantResources[got.GetAddress()], got)
+			assert.Equal(t, wantResources[got.GetAddress()], withoutProvenance(got))
 		} else {
 			// Anything else should be unsupported
 			assert.IsType(t, resources.UnsupportedResource{}, got)
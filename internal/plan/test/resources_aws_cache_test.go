@@ -0,0 +1,114 @@
+package plan_test
+
+import (
+	"testing"
+
+	"github.com/carboniferio/carbonifer/internal/plan"
+	"github.com/carboniferio/carbonifer/internal/providers"
+	"github.com/carboniferio/carbonifer/internal/resources"
+	"github.com/carboniferio/carbonifer/internal/terraform"
+	"github.com/shopspring/decimal"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetResource_AWSCacheAndSearch(t *testing.T) {
+
+	wantResources := map[string]resources.Resource{
+		"aws_elasticache_cluster.memcached": resources.ComputeResource{
+			Identification: &resources.ResourceIdentification{
+				Name:              "memcached",
+				Address:           "aws_elasticache_cluster.memcached",
+				ResourceType:      "aws_elasticache_cluster",
+				Provider:          providers.AWS,
+				Region:            "eu-west-3",
+				Count:             2,
+				ReplicationFactor: 1,
+			},
+			Specs: &resources.ComputeResourceSpecs{
+				VCPUs:      int32(2),
+				MemoryMb:   int32(8192),
+				HddStorage: decimal.Zero,
+				SsdStorage: decimal.Zero,
+			},
+		},
+		"aws_elasticache_replication_group.single": resources.ComputeResource{
+			Identification: &resources.ResourceIdentification{
+				Name:              "single",
+				Address:           "aws_elasticache_replication_group.single",
+				ResourceType:      "aws_elasticache_replication_group",
+				Provider:          providers.AWS,
+				Region:            "eu-west-3",
+				Count:             1,
+				ReplicationFactor: 3,
+			},
+			Specs: &resources.ComputeResourceSpecs{
+				VCPUs:      int32(2),
+				MemoryMb:   int32(16384),
+				HddStorage: decimal.Zero,
+				SsdStorage: decimal.Zero,
+			},
+		},
+		"aws_elasticache_replication_group.sharded": resources.ComputeResource{
+			Identification: &resources.ResourceIdentification{
+				Name:              "sharded",
+				Address:           "aws_elasticache_replication_group.sharded",
+				ResourceType:      "aws_elasticache_replication_group",
+				Provider:          providers.AWS,
+				Region:            "eu-west-3",
+				Count:             3,
+				ReplicationFactor: 3,
+			},
+			Specs: &resources.ComputeResourceSpecs{
+				VCPUs:      int32(2),
+				MemoryMb:   int32(1024),
+				HddStorage: decimal.Zero,
+				SsdStorage: decimal.Zero,
+			},
+		},
+		"aws_memorydb_cluster.first": resources.ComputeResource{
+			Identification: &resources.ResourceIdentification{
+				Name:              "first",
+				Address:           "aws_memorydb_cluster.first",
+				ResourceType:      "aws_memorydb_cluster",
+				Provider:          providers.AWS,
+				Region:            "eu-west-3",
+				Count:             2,
+				ReplicationFactor: 2,
+			},
+			Specs: &resources.ComputeResourceSpecs{
+				VCPUs:      int32(2),
+				MemoryMb:   int32(16384),
+				HddStorage: decimal.Zero,
+				SsdStorage: decimal.Zero,
+			},
+		},
+		"aws_opensearch_domain.first": resources.ComputeResource{
+			Identification: &resources.ResourceIdentification{
+				Name:              "first",
+				Address:           "aws_opensearch_domain.first",
+				ResourceType:      "aws_opensearch_domain",
+				Provider:          providers.AWS,
+				Region:            "eu-west-3",
+				Count:             3,
+				ReplicationFactor: 1,
+			},
+			Specs: &resources.ComputeResourceSpecs{
+				VCPUs:      int32(2),
+				MemoryMb:   int32(16384),
+				HddStorage: decimal.Zero,
+				SsdStorage: decimal.NewFromInt(100),
+			},
+		},
+	}
+	tfPlan, err := terraform.CarboniferPlan("test/terraform/planJson/aws_cache_search.json")
+	assert.NoError(t, err)
+	gotResources, err := plan.GetResources(viper.GetViper(), tfPlan)
+	assert.NoError(t, err)
+	assert.Equal(t, len(wantResources), len(gotResources))
+	for _, got := range gotResources {
+		assert.Equal(t, wantResources[got.GetAddress()], withoutProvenance(got))
+	}
+	// Estimated with its replication group, not reported as unsupported
+	assert.NotContains(t, gotResources, "aws_elasticache_cluster.member")
+}
@@ -79,10 +79,10 @@ func TestGetResource_EC2(t *testing.T) {
 	}
 	tfPlan, err := terraform.TerraformPlan()
 	assert.NoError(t, err)
-	gotResources, err := plan.GetResources(tfPlan)
+	gotResources, err := plan.GetResources(viper.GetViper(), tfPlan)
 	assert.NoError(t, err)
 	for _, res := range gotResources {
-		assert.Equal(t, wantResources[res.GetAddress()], res)
+		assert.Equal(t, wantResources[res.GetAddress()], withoutProvenance(res))
 
 	}
 }
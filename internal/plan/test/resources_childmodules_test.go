@@ -100,9 +100,9 @@ func TestGetResource_ChildModules(t *testing.T) {
 	}
 	tfPlan, err := terraform.TerraformPlan()
 	assert.NoError(t, err)
-	gotResources, err := plan.GetResources(tfPlan)
+	gotResources, err := plan.GetResources(viper.GetViper(), tfPlan)
 	assert.NoError(t, err)
 	for _, got := range gotResources {
-		assert.Equal(t, wantResources[got.GetAddress()], got)
+		assert.Equal(t, wantResources[got.GetAddress()], withoutProvenance(got))
 	}
 }
@@ -0,0 +1,34 @@
+package plan_test
+
+import (
+	"testing"
+
+	"github.com/carboniferio/carbonifer/internal/estimate"
+	"github.com/carboniferio/carbonifer/internal/plan"
+	"github.com/carboniferio/carbonifer/internal/resources"
+	"github.com/carboniferio/carbonifer/internal/terraform"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetResource_CPUPlatformPolicy(t *testing.T) {
+	tfPlan, err := terraform.CarboniferPlan("test/terraform/planJson/schedules.json")
+	assert.NoError(t, err)
+
+	// Flat policy, CPU platforms of machine types are not needed
+	gotResources, err := plan.GetResources(viper.GetViper(), tfPlan)
+	assert.NoError(t, err)
+	instance := gotResources["google_compute_instance.office"].(resources.ComputeResource)
+	assert.Nil(t, instance.Specs.CPUTypes)
+
+	viper.Set("provider.gcp.cpu_platform_policy", "max")
+	defer viper.Set("provider.gcp.cpu_platform_policy", "flat")
+	gotResources, err = plan.GetResources(viper.GetViper(), tfPlan)
+	assert.NoError(t, err)
+	instance = gotResources["google_compute_instance.office"].(resources.ComputeResource)
+	assert.Equal(t, []string{"Skylake", "Broadwell", "Haswell", "Sandy Bridge", "Ivy Bridge"}, instance.Specs.CPUTypes)
+
+	estimation, err := estimate.EstimateResource(viper.GetViper(), instance)
+	assert.NoError(t, err)
+	assert.Equal(t, "Sandy Bridge", estimation.CPUPlatform)
+}
@@ -106,6 +106,8 @@ func TestGetResource_GCP_GKE(t *testing.T) {
 				Provider:          providers.GCP,
 				Region:            "europe-west9",
 				Count:             12,
+				CountLow:          8,
+				CountHigh:         16,
 				ReplicationFactor: 3,
 				Address:           "google_container_cluster.my_cluster_autoscaled",
 			},
@@ -124,6 +126,8 @@ func TestGetResource_GCP_GKE(t *testing.T) {
 				Provider:          providers.GCP,
 				Region:            "europe-west9",
 				Count:             12,
+				CountLow:          8,
+				CountHigh:         16,
 				ReplicationFactor: 1,
 				Address:           "google_container_cluster.my_cluster_autoscaled_monozone",
 			},
@@ -142,6 +146,8 @@ func TestGetResource_GCP_GKE(t *testing.T) {
 				Provider:          providers.GCP,
 				Region:            "europe-west9",
 				Count:             70,
+				CountLow:          55,
+				CountHigh:         85,
 				ReplicationFactor: 1,
 				Address:           "google_container_cluster.my_cluster_autoscaled_total",
 			},
@@ -156,14 +162,14 @@ func TestGetResource_GCP_GKE(t *testing.T) {
 	}
 	tfPlan, err := terraform.TerraformPlan()
 	assert.NoError(t, err)
-	gotResources, err := plan.GetResources(tfPlan)
+	gotResources, err := plan.GetResources(viper.GetViper(), tfPlan)
 	assert.NoError(t, err)
 	for _, got := range gotResources {
 		if got.GetIdentification().ResourceType == "google_container_node_pool" {
 			// This should not exists, it should be ignored
 			assert.Fail(t, "google_container_node_pool should be ignored")
 		} else if got.GetIdentification().ResourceType == "google_container_cluster" {
-			assert.Equal(t, wantResources[got.GetAddress()], got)
+			assert.Equal(t, wantResources[got.GetAddress()], withoutProvenance(got))
 		} else {
 			// Anything else should be unsupported
 			assert.IsType(t, resources.UnsupportedResource{}, got)
@@ -0,0 +1,61 @@
+package plan_test
+
+import (
+	"testing"
+
+	"github.com/carboniferio/carbonifer/internal/plan"
+	"github.com/carboniferio/carbonifer/internal/providers"
+	"github.com/carboniferio/carbonifer/internal/resources"
+	"github.com/carboniferio/carbonifer/internal/terraform"
+	"github.com/shopspring/decimal"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetResource_GCPRedis(t *testing.T) {
+
+	wantResources := map[string]resources.Resource{
+		"google_redis_instance.basic": resources.ComputeResource{
+			Identification: &resources.ResourceIdentification{
+				Name:              "basic",
+				Address:           "google_redis_instance.basic",
+				ResourceType:      "google_redis_instance",
+				Provider:          providers.GCP,
+				Region:            "europe-west9",
+				Count:             1,
+				ReplicationFactor: 1,
+			},
+			Specs: &resources.ComputeResourceSpecs{
+				VCPUs:      int32(1),
+				MemoryMb:   int32(1024),
+				HddStorage: decimal.Zero,
+				SsdStorage: decimal.Zero,
+			},
+		},
+		"google_redis_instance.ha": resources.ComputeResource{
+			Identification: &resources.ResourceIdentification{
+				Name:              "ha",
+				Address:           "google_redis_instance.ha",
+				ResourceType:      "google_redis_instance",
+				Provider:          providers.GCP,
+				Region:            "europe-west9",
+				Count:             1,
+				ReplicationFactor: 3,
+			},
+			Specs: &resources.ComputeResourceSpecs{
+				VCPUs:      int32(2),
+				MemoryMb:   int32(16384),
+				HddStorage: decimal.Zero,
+				SsdStorage: decimal.Zero,
+			},
+		},
+	}
+	tfPlan, err := terraform.CarboniferPlan("test/terraform/planJson/gcp_redis.json")
+	assert.NoError(t, err)
+	gotResources, err := plan.GetResources(viper.GetViper(), tfPlan)
+	assert.NoError(t, err)
+	assert.Equal(t, len(wantResources), len(gotResources))
+	for _, got := range gotResources {
+		assert.Equal(t, wantResources[got.GetAddress()], withoutProvenance(got))
+	}
+}
@@ -87,7 +87,7 @@ var gpuDefaultMachine tfjson.StateResource = tfjson.StateResource{
 }
 
 func TestGetResource(t *testing.T) {
-	mapping, err := plan.GetMapping()
+	mapping, err := plan.GetMapping(viper.GetViper())
 	assert.NoError(t, err)
 	computeResourceMapping := *mapping.ComputeResource
 	type args struct {
@@ -222,12 +222,12 @@ func TestGetResource(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			resource, _ := testutils.TfResourceToJSON(&tt.args.tfResource)
-			got, err := plan.GetComputeResource(*resource, &tt.args.mapping, nil)
+			got, err := plan.GetComputeResource(viper.GetViper(), *resource, &tt.args.mapping, nil)
 			assert.NoError(t, err)
 			assert.Len(t, got, 1)
 			assert.IsType(t, resources.ComputeResource{}, got[0])
 			gotResource := got[0].(resources.ComputeResource)
-			assert.Equal(t, tt.want, gotResource)
+			assert.Equal(t, tt.want, withoutProvenance(gotResource))
 			assert.NoError(t, err)
 		})
 	}
@@ -266,12 +266,12 @@ func TestGetResources_DiskImage(t *testing.T) {
 	}
 
 	tfPlan, _ := terraform.TerraformPlan()
-	resourceList, err := plan.GetResources(tfPlan)
+	resourceList, err := plan.GetResources(viper.GetViper(), tfPlan)
 	if assert.NoError(t, err) {
 		assert.Equal(t, len(wantResources), len(resourceList))
 		for i, resource := range resourceList {
 			wantResource := wantResources[i]
-			assert.EqualValues(t, wantResource, resource)
+			assert.EqualValues(t, wantResource, withoutProvenance(resource))
 		}
 	}
 
@@ -327,11 +327,11 @@ func TestGetResources_GroupInstance(t *testing.T) {
 	}
 
 	tfPlan, _ := terraform.TerraformPlan()
-	resources, err := plan.GetResources(tfPlan)
+	resources, err := plan.GetResources(viper.GetViper(), tfPlan)
 	if assert.NoError(t, err) {
 		for i, resource := range resources {
 			wantResource := wantResources[i]
-			assert.EqualValues(t, wantResource, resource)
+			assert.EqualValues(t, wantResource, withoutProvenance(resource))
 		}
 	}
 
@@ -387,11 +387,11 @@ func TestGetResources_InstanceFromTemplate(t *testing.T) {
 	}
 
 	tfPlan, _ := terraform.TerraformPlan()
-	resources, err := plan.GetResources(tfPlan)
+	resources, err := plan.GetResources(viper.GetViper(), tfPlan)
 	if assert.NoError(t, err) {
 		for i, resource := range resources {
 			wantResource := wantResources[i]
-			assert.EqualValues(t, wantResource, resource)
+			assert.EqualValues(t, wantResource, withoutProvenance(resource))
 		}
 	}
 
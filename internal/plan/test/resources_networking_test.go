@@ -0,0 +1,149 @@
+package plan_test
+
+import (
+	"testing"
+
+	"github.com/carboniferio/carbonifer/internal/plan"
+	"github.com/carboniferio/carbonifer/internal/providers"
+	"github.com/carboniferio/carbonifer/internal/resources"
+	"github.com/carboniferio/carbonifer/internal/terraform"
+	"github.com/shopspring/decimal"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetResource_Networking(t *testing.T) {
+	wantResources := map[string]resources.Resource{
+		"aws_lb.front": resources.ComputeResource{
+			Identification: &resources.ResourceIdentification{
+				Name:              "front",
+				Address:           "aws_lb.front",
+				ResourceType:      "aws_lb",
+				Provider:          providers.AWS,
+				Region:            "eu-west-3",
+				Count:             1,
+				ReplicationFactor: 1,
+			},
+			Specs: &resources.ComputeResourceSpecs{
+				HddStorage:      decimal.Zero,
+				SsdStorage:      decimal.Zero,
+				NetworkEgressGb: decimal.NewFromInt(2000),
+			},
+		},
+		"aws_nat_gateway.main": resources.ComputeResource{
+			Identification: &resources.ResourceIdentification{
+				Name:              "main",
+				Address:           "aws_nat_gateway.main",
+				ResourceType:      "aws_nat_gateway",
+				Provider:          providers.AWS,
+				Region:            "eu-west-3",
+				Count:             1,
+				ReplicationFactor: 1,
+			},
+			Specs: &resources.ComputeResourceSpecs{
+				HddStorage:      decimal.Zero,
+				SsdStorage:      decimal.Zero,
+				NetworkEgressGb: decimal.NewFromInt(0),
+			},
+		},
+		"aws_cloudfront_distribution.cdn": resources.ComputeResource{
+			Identification: &resources.ResourceIdentification{
+				Name:              "cdn",
+				Address:           "aws_cloudfront_distribution.cdn",
+				ResourceType:      "aws_cloudfront_distribution",
+				Provider:          providers.AWS,
+				Region:            "eu-west-3",
+				Count:             1,
+				ReplicationFactor: 1,
+			},
+			Specs: &resources.ComputeResourceSpecs{
+				HddStorage:      decimal.Zero,
+				SsdStorage:      decimal.Zero,
+				NetworkEgressGb: decimal.NewFromInt(10000),
+			},
+		},
+		"aws_instance.web": resources.ComputeResource{
+			Identification: &resources.ResourceIdentification{
+				Name:              "web",
+				Address:           "aws_instance.web",
+				ResourceType:      "aws_instance",
+				Provider:          providers.AWS,
+				Region:            "eu-west-3",
+				Count:             1,
+				ReplicationFactor: 1,
+			},
+			Specs: &resources.ComputeResourceSpecs{
+				VCPUs:           int32(1),
+				MemoryMb:        int32(1024),
+				HddStorage:      decimal.Zero,
+				SsdStorage:      decimal.Zero,
+				NetworkEgressGb: decimal.NewFromInt(300),
+			},
+		},
+		"google_compute_global_forwarding_rule.https": resources.ComputeResource{
+			Identification: &resources.ResourceIdentification{
+				Name:              "https",
+				Address:           "google_compute_global_forwarding_rule.https",
+				ResourceType:      "google_compute_global_forwarding_rule",
+				Provider:          providers.GCP,
+				Region:            "europe-west9",
+				Count:             1,
+				ReplicationFactor: 1,
+			},
+			Specs: &resources.ComputeResourceSpecs{
+				HddStorage:      decimal.Zero,
+				SsdStorage:      decimal.Zero,
+				NetworkEgressGb: decimal.NewFromInt(1500),
+			},
+		},
+		"google_compute_router_nat.nat": resources.ComputeResource{
+			Identification: &resources.ResourceIdentification{
+				Name:              "nat",
+				Address:           "google_compute_router_nat.nat",
+				ResourceType:      "google_compute_router_nat",
+				Provider:          providers.GCP,
+				Region:            "europe-west1",
+				Count:             1,
+				ReplicationFactor: 1,
+			},
+			Specs: &resources.ComputeResourceSpecs{
+				HddStorage:      decimal.Zero,
+				SsdStorage:      decimal.Zero,
+				NetworkEgressGb: decimal.NewFromInt(0),
+			},
+		},
+		"google_compute_backend_bucket.static": resources.ComputeResource{
+			Identification: &resources.ResourceIdentification{
+				Name:              "static",
+				Address:           "google_compute_backend_bucket.static",
+				ResourceType:      "google_compute_backend_bucket",
+				Provider:          providers.GCP,
+				Region:            "europe-west9",
+				Count:             1,
+				ReplicationFactor: 1,
+			},
+			Specs: &resources.ComputeResourceSpecs{
+				HddStorage:      decimal.Zero,
+				SsdStorage:      decimal.Zero,
+				NetworkEgressGb: decimal.NewFromInt(0),
+			},
+		},
+		"google_compute_backend_service.api": resources.UnsupportedResource{
+			Identification: &resources.ResourceIdentification{
+				Name:         "api",
+				Address:      "google_compute_backend_service.api",
+				ResourceType: "google_compute_backend_service",
+				Provider:     providers.GCP,
+				Count:        1,
+			},
+		},
+	}
+	tfPlan, err := terraform.CarboniferPlan("test/terraform/planJson/networking.json")
+	assert.NoError(t, err)
+	gotResources, err := plan.GetResources(viper.GetViper(), tfPlan)
+	assert.NoError(t, err)
+	assert.Equal(t, len(wantResources), len(gotResources))
+	for _, got := range gotResources {
+		assert.Equal(t, wantResources[got.GetAddress()], withoutProvenance(got))
+	}
+}
@@ -0,0 +1,154 @@
+package plan_test
+
+import (
+	"testing"
+
+	"github.com/carboniferio/carbonifer/internal/plan"
+	"github.com/carboniferio/carbonifer/internal/providers"
+	"github.com/carboniferio/carbonifer/internal/resources"
+	"github.com/carboniferio/carbonifer/internal/terraform"
+	"github.com/shopspring/decimal"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetResource_ObjectStorage(t *testing.T) {
+
+	viper.Set("provider.gcp.avg_bucket_size_gb", 100)
+	defer viper.Set("provider.gcp.avg_bucket_size_gb", 0)
+
+	wantResources := map[string]resources.Resource{
+		"aws_s3_bucket.logs": resources.ComputeResource{
+			Identification: &resources.ResourceIdentification{
+				Name:              "logs",
+				Address:           "aws_s3_bucket.logs",
+				ResourceType:      "aws_s3_bucket",
+				Provider:          providers.AWS,
+				Region:            "eu-west-3",
+				Count:             1,
+				ReplicationFactor: 6,
+			},
+			Specs: &resources.ComputeResourceSpecs{
+				HddStorage: decimal.NewFromInt(500),
+				SsdStorage: decimal.Zero,
+			},
+		},
+		"aws_s3_bucket.replica": resources.ComputeResource{
+			Identification: &resources.ResourceIdentification{
+				Name:              "replica",
+				Address:           "aws_s3_bucket.replica",
+				ResourceType:      "aws_s3_bucket",
+				Provider:          providers.AWS,
+				Region:            "eu-west-3",
+				Count:             1,
+				ReplicationFactor: 3,
+			},
+			Specs: &resources.ComputeResourceSpecs{
+				HddStorage: decimal.NewFromInt(0),
+				SsdStorage: decimal.Zero,
+			},
+		},
+		"google_storage_bucket.regional": resources.ComputeResource{
+			Identification: &resources.ResourceIdentification{
+				Name:              "regional",
+				Address:           "google_storage_bucket.regional",
+				ResourceType:      "google_storage_bucket",
+				Provider:          providers.GCP,
+				Region:            "europe-west9",
+				Count:             1,
+				ReplicationFactor: 2,
+			},
+			Specs: &resources.ComputeResourceSpecs{
+				HddStorage: decimal.NewFromInt(200),
+				SsdStorage: decimal.Zero,
+			},
+		},
+		"google_storage_bucket.multi": resources.ComputeResource{
+			Identification: &resources.ResourceIdentification{
+				Name:              "multi",
+				Address:           "google_storage_bucket.multi",
+				ResourceType:      "google_storage_bucket",
+				Provider:          providers.GCP,
+				Region:            "europe-west1",
+				Count:             1,
+				ReplicationFactor: 6,
+			},
+			Specs: &resources.ComputeResourceSpecs{
+				HddStorage: decimal.NewFromInt(1024),
+				SsdStorage: decimal.Zero,
+			},
+		},
+		"google_storage_bucket.dual": resources.ComputeResource{
+			Identification: &resources.ResourceIdentification{
+				Name:              "dual",
+				Address:           "google_storage_bucket.dual",
+				ResourceType:      "google_storage_bucket",
+				Provider:          providers.GCP,
+				Region:            "europe-west4",
+				Count:             1,
+				ReplicationFactor: 4,
+			},
+			Specs: &resources.ComputeResourceSpecs{
+				HddStorage: decimal.NewFromInt(100),
+				SsdStorage: decimal.Zero,
+			},
+		},
+		"google_storage_bucket.archive": resources.ComputeResource{
+			Identification: &resources.ResourceIdentification{
+				Name:              "archive",
+				Address:           "google_storage_bucket.archive",
+				ResourceType:      "google_storage_bucket",
+				Provider:          providers.GCP,
+				Region:            "europe-west9",
+				Count:             1,
+				ReplicationFactor: 2,
+			},
+			Specs: &resources.ComputeResourceSpecs{
+				HddStorage:     decimal.Zero,
+				SsdStorage:     decimal.Zero,
+				ArchiveStorage: decimal.NewFromInt(2048),
+			},
+		},
+		// Lifecycle rule of a for_each bucket, to a single availability zone
+		"aws_s3_bucket.onezone[\"scratch\"]": resources.ComputeResource{
+			Identification: &resources.ResourceIdentification{
+				Name:              "onezone[\"scratch\"]",
+				Address:           "aws_s3_bucket.onezone[\"scratch\"]",
+				ResourceType:      "aws_s3_bucket",
+				Provider:          providers.AWS,
+				Region:            "eu-west-3",
+				Count:             1,
+				ReplicationFactor: 1,
+			},
+			Specs: &resources.ComputeResourceSpecs{
+				HddStorage: decimal.NewFromInt(100),
+				SsdStorage: decimal.Zero,
+			},
+		},
+		// Lifecycle and replication rules declared in a child module
+		"module.backup[0].aws_s3_bucket.this": resources.ComputeResource{
+			Identification: &resources.ResourceIdentification{
+				Name:              "this",
+				Address:           "module.backup[0].aws_s3_bucket.this",
+				ResourceType:      "aws_s3_bucket",
+				Provider:          providers.AWS,
+				Region:            "eu-west-3",
+				Count:             1,
+				ReplicationFactor: 6,
+			},
+			Specs: &resources.ComputeResourceSpecs{
+				HddStorage:     decimal.Zero,
+				SsdStorage:     decimal.Zero,
+				ArchiveStorage: decimal.NewFromInt(1000),
+			},
+		},
+	}
+	tfPlan, err := terraform.CarboniferPlan("test/terraform/planJson/object_storage.json")
+	assert.NoError(t, err)
+	gotResources, err := plan.GetResources(viper.GetViper(), tfPlan)
+	assert.NoError(t, err)
+	assert.Equal(t, len(wantResources), len(gotResources))
+	for _, got := range gotResources {
+		assert.Equal(t, wantResources[got.GetAddress()], withoutProvenance(got))
+	}
+}
@@ -0,0 +1,36 @@
+package plan_test
+
+import (
+	"testing"
+
+	"github.com/carboniferio/carbonifer/internal/estimate"
+	"github.com/carboniferio/carbonifer/internal/plan"
+	"github.com/carboniferio/carbonifer/internal/resources"
+	"github.com/carboniferio/carbonifer/internal/terraform"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetResource_Partial(t *testing.T) {
+	tfPlan, err := terraform.CarboniferPlan("test/terraform/planJson/partial.json")
+	assert.NoError(t, err)
+	gotResources, err := plan.GetResources(viper.GetViper(), tfPlan)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(gotResources))
+
+	assert.IsType(t, resources.ComputeResource{}, gotResources["google_compute_disk.valid"])
+	failed, ok := gotResources["google_compute_disk.broken"].(resources.FailedResource)
+	assert.True(t, ok)
+	assert.ErrorContains(t, failed.Err, "big")
+
+	report := estimate.EstimateResources(viper.GetViper(), gotResources)
+	assert.Equal(t, 2, len(report.Resources))
+	for _, estimation := range report.Resources {
+		if estimation.Resource.GetAddress() == "google_compute_disk.broken" {
+			assert.Contains(t, estimation.Error, "big")
+		} else {
+			assert.Empty(t, estimation.Error)
+		}
+	}
+	assert.Equal(t, "1", report.Total.ResourcesCount.String())
+}
@@ -0,0 +1,109 @@
+package plan_test
+
+import (
+	"testing"
+
+	"github.com/carboniferio/carbonifer/internal/plan"
+	"github.com/carboniferio/carbonifer/internal/providers"
+	"github.com/carboniferio/carbonifer/internal/resources"
+	"github.com/carboniferio/carbonifer/internal/terraform"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+// withoutProvenance returns a copy of the resource without the provenance of its properties, to compare it with an
+// expected resource
+func withoutProvenance(resource resources.Resource) resources.Resource {
+	computeResource, ok := resource.(resources.ComputeResource)
+	if !ok || computeResource.Specs == nil {
+		return resource
+	}
+	specs := *computeResource.Specs
+	specs.Provenance = nil
+	computeResource.Specs = &specs
+	return computeResource
+}
+
+func TestGetResource_Provenance(t *testing.T) {
+	tfPlan, err := terraform.CarboniferPlan("test/terraform/planJson/plan.json")
+	assert.NoError(t, err)
+	resourceList, err := plan.GetResources(viper.GetViper(), tfPlan)
+	assert.NoError(t, err)
+
+	instance := resourceList["google_compute_instance.default[0]"].(resources.ComputeResource)
+	provenance := instance.Specs.Provenance
+	assert.Equal(t, resources.Provenance{
+		Source:    resources.ProvenanceDataFile,
+		Path:      ".values.machine_type",
+		Reference: "gcp_instances.json (.vcpus)",
+		Key:       "n1-standard-2",
+	}, provenance["vCPUs"])
+	assert.Equal(t, resources.ProvenanceDataFile, provenance["memory"].Source)
+	assert.Equal(t, resources.ProvenanceRegex, provenance["region"].Source)
+	assert.Equal(t, ".values.zone", provenance["region"].Path)
+	assert.Equal(t, resources.ProvenanceDefault, provenance["replication_factor"].Source)
+	assert.Equal(t, resources.ProvenancePlan, provenance["guest_accelerator[0].type"].Source)
+	assert.Equal(t, resources.ProvenanceDefault, provenance["storage[0].type"].Source)
+	assert.Equal(t, "general.disk_types", provenance["storage[0].type"].Reference)
+	assert.NotContains(t, provenance, "count")
+
+	// Custom machine types are read by a regex
+	mapping, err := plan.GetMapping(viper.GetViper())
+	assert.NoError(t, err)
+	computeMapping := (*mapping.ComputeResource)["google_compute_instance"]
+	got, err := plan.GetComputeResource(viper.GetViper(), map[string]interface{}{
+		"address":       "google_compute_instance.custom",
+		"type":          "google_compute_instance",
+		"name":          "custom",
+		"provider_name": "google",
+		"values": map[string]interface{}{
+			"name":         "custom",
+			"machine_type": "custom-4-8192",
+			"zone":         "europe-west9-a",
+		},
+	}, &computeMapping, nil)
+	assert.NoError(t, err)
+	custom := got[0].(resources.ComputeResource)
+	assert.Equal(t, resources.ProvenanceRegex, custom.Specs.Provenance["vCPUs"].Source)
+	assert.Equal(t, ".*custom-([0-9]+)-.*", custom.Specs.Provenance["vCPUs"].Regex)
+
+	// Unknown instance types are not found in the instance data file, and reported with suggestions
+	awsMapping := (*mapping.ComputeResource)["aws_instance"]
+	_, err = plan.GetComputeResource(viper.GetViper(), map[string]interface{}{
+		"address":       "aws_instance.unknown",
+		"type":          "aws_instance",
+		"name":          "unknown",
+		"provider_name": "registry.terraform.io/hashicorp/aws",
+		"values": map[string]interface{}{
+			"instance_type":     "t2.micra",
+			"availability_zone": "eu-west-3a",
+		},
+	}, &awsMapping, nil)
+	var unknownInstanceTypeError *providers.UnknownInstanceTypeError
+	assert.ErrorAs(t, err, &unknownInstanceTypeError)
+	assert.Equal(t, "t2.micra", unknownInstanceTypeError.InstanceType)
+	assert.Contains(t, unknownInstanceTypeError.Suggestions, "t2.micro")
+
+	// Keys missing from other data files are not unknown instance types
+	sqlMapping := (*mapping.ComputeResource)["google_sql_database_instance"]
+	got, err = plan.GetComputeResource(viper.GetViper(), map[string]interface{}{
+		"address":       "google_sql_database_instance.unknown",
+		"type":          "google_sql_database_instance",
+		"name":          "unknown",
+		"provider_name": "registry.terraform.io/hashicorp/google",
+		"values": map[string]interface{}{
+			"name":     "unknown",
+			"region":   "europe-west9",
+			"settings": []interface{}{map[string]interface{}{"tier": "db-unknown-1", "disk_size": 10, "disk_type": "PD_SSD"}},
+		},
+	}, &sqlMapping, nil)
+	assert.NoError(t, err)
+	unknownTier := got[0].(resources.ComputeResource)
+	assert.Equal(t, int32(0), unknownTier.Specs.VCPUs)
+	assert.Equal(t, resources.Provenance{
+		Source:    resources.ProvenanceNotFound,
+		Path:      ".values.settings[0].tier",
+		Reference: "gcp_sql_tiers.json (.vcpus)",
+		Key:       "db-unknown-1",
+	}, unknownTier.Specs.Provenance["vCPUs"])
+}
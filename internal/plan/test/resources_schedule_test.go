@@ -0,0 +1,58 @@
+package plan_test
+
+import (
+	"testing"
+
+	"github.com/carboniferio/carbonifer/internal/plan"
+	"github.com/carboniferio/carbonifer/internal/resources"
+	"github.com/carboniferio/carbonifer/internal/terraform"
+	"github.com/shopspring/decimal"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetResource_Schedules(t *testing.T) {
+
+	tfPlan, err := terraform.CarboniferPlan("test/terraform/planJson/schedules.json")
+	assert.NoError(t, err)
+	gotResources, err := plan.GetResources(viper.GetViper(), tfPlan)
+	assert.NoError(t, err)
+
+	wantUsageRatios := map[string]decimal.Decimal{
+		// Instance schedule policy: 10 hours a day, 5 days a week
+		"google_compute_instance.office": decimal.NewFromInt(50).Div(decimal.NewFromInt(168)),
+		"google_compute_instance.always": decimal.NewFromInt(0),
+		// Scheduled actions: started at 8:00, stopped at 20:00
+		"aws_autoscaling_group.workers": decimal.NewFromInt(12).Div(decimal.NewFromInt(24)),
+		// Only a stop action, ignored
+		"aws_autoscaling_group.manual": decimal.NewFromInt(0),
+		// Stopped as soon as started, no instance is running
+		"aws_autoscaling_group.retired": decimal.NewFromInt(0),
+		// Instance schedule policy of a child module
+		"module.lab.google_compute_instance.this[0]": decimal.NewFromInt(50).Div(decimal.NewFromInt(168)),
+	}
+	assert.Equal(t, len(wantUsageRatios), len(gotResources))
+	for address, wantUsageRatio := range wantUsageRatios {
+		got, ok := gotResources[address].(resources.ComputeResource)
+		assert.True(t, ok, address)
+		assert.Equal(t, wantUsageRatio.String(), got.Identification.UsageRatio.String(), address)
+	}
+	retired := gotResources["aws_autoscaling_group.retired"].(resources.ComputeResource)
+	assert.Equal(t, int64(0), retired.Identification.Count)
+}
+
+func TestGetResource_SchedulesAssumptions(t *testing.T) {
+
+	viper.Set("assumptions_file", "test/config/assumptions_schedule.yaml")
+	defer viper.Set("assumptions_file", "")
+
+	tfPlan, err := terraform.CarboniferPlan("test/terraform/planJson/schedules.json")
+	assert.NoError(t, err)
+	gotResources, err := plan.GetResources(viper.GetViper(), tfPlan)
+	assert.NoError(t, err)
+
+	always := gotResources["google_compute_instance.always"].(resources.ComputeResource)
+	assert.Equal(t, decimal.NewFromInt(8).Div(decimal.NewFromInt(24)).String(), always.Identification.UsageRatio.String())
+	office := gotResources["google_compute_instance.office"].(resources.ComputeResource)
+	assert.Equal(t, decimal.NewFromInt(50).Div(decimal.NewFromInt(168)).String(), office.Identification.UsageRatio.String())
+}
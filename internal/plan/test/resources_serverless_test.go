@@ -0,0 +1,116 @@
+package plan_test
+
+import (
+	"testing"
+
+	"github.com/carboniferio/carbonifer/internal/plan"
+	"github.com/carboniferio/carbonifer/internal/providers"
+	"github.com/carboniferio/carbonifer/internal/resources"
+	"github.com/carboniferio/carbonifer/internal/terraform"
+	"github.com/shopspring/decimal"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetResource_Serverless(t *testing.T) {
+	wantResources := map[string]resources.Resource{
+		"aws_lambda_function.api": resources.ComputeResource{
+			Identification: &resources.ResourceIdentification{
+				Name:              "api",
+				Address:           "aws_lambda_function.api",
+				ResourceType:      "aws_lambda_function",
+				Provider:          providers.AWS,
+				Region:            "eu-west-3",
+				Count:             5,
+				ReplicationFactor: 1,
+				UsageRatio:        decimal.NewFromInt(1),
+			},
+			Specs: &resources.ComputeResourceSpecs{
+				FractionalVCPUs: decimal.NewFromFloat(1024.0 / 1769),
+				MemoryMb:        int32(1024),
+				HddStorage:      decimal.Zero,
+				SsdStorage:      decimal.Zero,
+			},
+		},
+		"aws_lambda_function.cron": resources.ComputeResource{
+			Identification: &resources.ResourceIdentification{
+				Name:              "cron",
+				Address:           "aws_lambda_function.cron",
+				ResourceType:      "aws_lambda_function",
+				Provider:          providers.AWS,
+				Region:            "eu-west-3",
+				Count:             1,
+				ReplicationFactor: 1,
+				UsageRatio:        decimal.NewFromFloat(0.2),
+			},
+			Specs: &resources.ComputeResourceSpecs{
+				FractionalVCPUs: decimal.NewFromFloat(128.0 / 1769),
+				MemoryMb:        int32(128),
+				HddStorage:      decimal.Zero,
+				SsdStorage:      decimal.Zero,
+			},
+		},
+		"google_cloud_run_v2_service.web": resources.ComputeResource{
+			Identification: &resources.ResourceIdentification{
+				Name:              "web",
+				Address:           "google_cloud_run_v2_service.web",
+				ResourceType:      "google_cloud_run_v2_service",
+				Provider:          providers.GCP,
+				Region:            "europe-west1",
+				Count:             3,
+				ReplicationFactor: 1,
+				UsageRatio:        decimal.NewFromInt(1),
+			},
+			Specs: &resources.ComputeResourceSpecs{
+				VCPUs:      int32(2),
+				MemoryMb:   int32(1024),
+				HddStorage: decimal.Zero,
+				SsdStorage: decimal.Zero,
+			},
+		},
+		"google_cloud_run_service.legacy": resources.ComputeResource{
+			Identification: &resources.ResourceIdentification{
+				Name:              "legacy",
+				Address:           "google_cloud_run_service.legacy",
+				ResourceType:      "google_cloud_run_service",
+				Provider:          providers.GCP,
+				Region:            "europe-west9",
+				Count:             1,
+				ReplicationFactor: 1,
+				UsageRatio:        decimal.NewFromInt(1),
+			},
+			Specs: &resources.ComputeResourceSpecs{
+				FractionalVCPUs: decimal.NewFromFloat(0.5),
+				MemoryMb:        int32(256),
+				HddStorage:      decimal.Zero,
+				SsdStorage:      decimal.Zero,
+			},
+		},
+		"google_cloudfunctions2_function.worker": resources.ComputeResource{
+			Identification: &resources.ResourceIdentification{
+				Name:              "worker",
+				Address:           "google_cloudfunctions2_function.worker",
+				ResourceType:      "google_cloudfunctions2_function",
+				Provider:          providers.GCP,
+				Region:            "europe-west9",
+				Count:             2,
+				ReplicationFactor: 1,
+				UsageRatio:        decimal.NewFromFloat(0.75),
+			},
+			Specs: &resources.ComputeResourceSpecs{
+				FractionalVCPUs: decimal.NewFromFloat(0.333),
+				MemoryMb:        int32(512),
+				HddStorage:      decimal.Zero,
+				SsdStorage:      decimal.Zero,
+			},
+		},
+	}
+	tfPlan, err := terraform.CarboniferPlan("test/terraform/planJson/serverless.json")
+	assert.NoError(t, err)
+	gotResources, err := plan.GetResources(viper.GetViper(), tfPlan)
+	assert.NoError(t, err)
+	assert.Equal(t, len(wantResources), len(gotResources))
+	for _, got := range gotResources {
+		assert.Equal(t, wantResources[got.GetAddress()], withoutProvenance(got))
+	}
+}
@@ -0,0 +1,38 @@
+package plan_test
+
+import (
+	"testing"
+
+	"github.com/carboniferio/carbonifer/internal/plan"
+	"github.com/carboniferio/carbonifer/internal/resources"
+	"github.com/carboniferio/carbonifer/internal/terraform"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetResource_CountRange(t *testing.T) {
+
+	tfPlan, err := terraform.CarboniferPlan("test/terraform/planJson/schedules.json")
+	assert.NoError(t, err)
+	gotResources, err := plan.GetResources(viper.GetViper(), tfPlan)
+	assert.NoError(t, err)
+
+	// min 1, max 3: average size 0.5 +/- 0.25
+	workers := gotResources["aws_autoscaling_group.workers"].(resources.ComputeResource)
+	assert.Equal(t, int64(2), workers.Identification.Count)
+	assert.Equal(t, int64(1), workers.Identification.CountLow)
+	assert.Equal(t, int64(2), workers.Identification.CountHigh)
+
+	// Not autoscaled
+	office := gotResources["google_compute_instance.office"].(resources.ComputeResource)
+	assert.Equal(t, int64(0), office.Identification.CountLow)
+	assert.Equal(t, int64(0), office.Identification.CountHigh)
+
+	viper.Set("uncertainty.avg_autoscaler_size_percent", 0)
+	defer viper.Set("uncertainty.avg_autoscaler_size_percent", 0.25)
+	gotResources, err = plan.GetResources(viper.GetViper(), tfPlan)
+	assert.NoError(t, err)
+	workers = gotResources["aws_autoscaling_group.workers"].(resources.ComputeResource)
+	assert.Equal(t, int64(0), workers.Identification.CountLow)
+	assert.Equal(t, int64(0), workers.Identification.CountHigh)
+}
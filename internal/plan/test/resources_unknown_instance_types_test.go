@@ -0,0 +1,64 @@
+package plan_test
+
+import (
+	"testing"
+
+	"github.com/carboniferio/carbonifer/internal/plan"
+	"github.com/carboniferio/carbonifer/internal/providers"
+	"github.com/carboniferio/carbonifer/internal/resources"
+	"github.com/carboniferio/carbonifer/internal/terraform"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetResource_UnknownInstanceType(t *testing.T) {
+	tfPlan, err := terraform.CarboniferPlan("test/terraform/planJson/unknown_instance_types.json")
+	assert.NoError(t, err)
+	gotResources, err := plan.GetResources(viper.GetViper(), tfPlan)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(gotResources))
+
+	var unknownInstanceTypeError *providers.UnknownInstanceTypeError
+	web, ok := gotResources["aws_instance.web"].(resources.FailedResource)
+	assert.True(t, ok)
+	assert.ErrorAs(t, web.Err, &unknownInstanceTypeError)
+	assert.Equal(t, "t2.micra", unknownInstanceTypeError.InstanceType)
+	assert.Equal(t, []string{"t2.micro", "t2.large", "t2.medium"}, unknownInstanceTypeError.Suggestions)
+
+	// Not an error of the regex of custom machine types
+	dev, ok := gotResources["google_compute_instance.dev"].(resources.FailedResource)
+	assert.True(t, ok)
+	assert.ErrorAs(t, dev.Err, &unknownInstanceTypeError)
+	assert.Equal(t, "n1-standard-3", unknownInstanceTypeError.InstanceType)
+	assert.Equal(t, "n1-standard-2", unknownInstanceTypeError.Suggestions[0])
+}
+
+func TestGetResource_UnknownInstanceTypeFamilyAverage(t *testing.T) {
+	viper.Set("provider.aws.unknown_instance_type_policy", plan.UnknownInstanceTypePolicyFamilyAverage)
+	viper.Set("provider.gcp.unknown_instance_type_policy", plan.UnknownInstanceTypePolicyFamilyAverage)
+	defer viper.Set("provider.aws.unknown_instance_type_policy", plan.UnknownInstanceTypePolicyError)
+	defer viper.Set("provider.gcp.unknown_instance_type_policy", plan.UnknownInstanceTypePolicyError)
+
+	tfPlan, err := terraform.CarboniferPlan("test/terraform/planJson/unknown_instance_types.json")
+	assert.NoError(t, err)
+	gotResources, err := plan.GetResources(viper.GetViper(), tfPlan)
+	assert.NoError(t, err)
+
+	batch := gotResources["aws_instance.batch"].(resources.ComputeResource)
+	assert.Equal(t, int32(12), batch.Specs.VCPUs)
+	assert.Equal(t, int32(12*4096), batch.Specs.MemoryMb)
+	assert.Equal(t, resources.ProvenanceFamilyAverage, batch.Specs.Provenance["vCPUs"].Source)
+	assert.Equal(t, "m5 family", batch.Specs.Provenance["memory"].Reference)
+	quality, warnings := batch.Specs.DataQuality()
+	assert.Equal(t, resources.DataQualityLow, quality)
+	assert.Contains(t, warnings, "memory, vCPUs: unknown 'm5.3xlarge' estimated from the average of the m5 family, did you mean 'm5.2xlarge', 'm5.4xlarge', 'm5.8xlarge'?")
+
+	dev := gotResources["google_compute_instance.dev"].(resources.ComputeResource)
+	assert.Equal(t, int32(3), dev.Specs.VCPUs)
+	assert.Equal(t, int32(3*3840), dev.Specs.MemoryMb)
+
+	// Size without vCPUs, those of the closest instance type of the family
+	web := gotResources["aws_instance.web"].(resources.ComputeResource)
+	assert.Equal(t, resources.ProvenanceFamilyAverage, web.Specs.Provenance["vCPUs"].Source)
+	assert.Equal(t, int32(1), web.Specs.VCPUs)
+}
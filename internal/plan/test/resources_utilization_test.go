@@ -0,0 +1,128 @@
+package plan_test
+
+import (
+	"testing"
+
+	"github.com/carboniferio/carbonifer/internal/plan"
+	"github.com/carboniferio/carbonifer/internal/providers"
+	"github.com/carboniferio/carbonifer/internal/resources"
+	"github.com/carboniferio/carbonifer/internal/terraform"
+	"github.com/shopspring/decimal"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetResource_Utilization(t *testing.T) {
+
+	viper.Set("assumptions_file", "test/config/assumptions.yaml")
+	defer viper.Set("assumptions_file", "")
+
+	wantResources := map[string]resources.Resource{
+		"aws_instance.batch": resources.ComputeResource{
+			Identification: &resources.ResourceIdentification{
+				Name:              "batch",
+				Address:           "aws_instance.batch",
+				ResourceType:      "aws_instance",
+				Provider:          providers.AWS,
+				Region:            "eu-west-3",
+				Count:             1,
+				ReplicationFactor: 1,
+			},
+			Specs: &resources.ComputeResourceSpecs{
+				VCPUs:           int32(1),
+				MemoryMb:        int32(1024),
+				HddStorage:      decimal.Zero,
+				SsdStorage:      decimal.Zero,
+				AvgCPUUse:       decimal.NewFromFloat(0.95),
+				AvgCPUUseSource: resources.SourceAssumptions,
+			},
+		},
+		"aws_instance.web": resources.ComputeResource{
+			Identification: &resources.ResourceIdentification{
+				Name:              "web",
+				Address:           "aws_instance.web",
+				ResourceType:      "aws_instance",
+				Provider:          providers.AWS,
+				Region:            "eu-west-3",
+				Count:             1,
+				ReplicationFactor: 1,
+				UsageRatio:        decimal.NewFromInt(12).Div(decimal.NewFromInt(24)),
+			},
+			Specs: &resources.ComputeResourceSpecs{
+				VCPUs:           int32(1),
+				MemoryMb:        int32(1024),
+				HddStorage:      decimal.Zero,
+				SsdStorage:      decimal.Zero,
+				AvgCPUUse:       decimal.NewFromFloat(0.3),
+				AvgCPUUseSource: resources.SourceTag,
+			},
+		},
+		"aws_instance.default": resources.ComputeResource{
+			Identification: &resources.ResourceIdentification{
+				Name:              "default",
+				Address:           "aws_instance.default",
+				ResourceType:      "aws_instance",
+				Provider:          providers.AWS,
+				Region:            "eu-west-3",
+				Count:             1,
+				ReplicationFactor: 1,
+				UsageRatio:        decimal.NewFromInt(12).Div(decimal.NewFromInt(24)),
+			},
+			Specs: &resources.ComputeResourceSpecs{
+				VCPUs:      int32(1),
+				MemoryMb:   int32(1024),
+				HddStorage: decimal.Zero,
+				SsdStorage: decimal.Zero,
+			},
+		},
+		"google_compute_instance.dev": resources.ComputeResource{
+			Identification: &resources.ResourceIdentification{
+				Name:              "dev",
+				Address:           "google_compute_instance.dev",
+				ResourceType:      "google_compute_instance",
+				Provider:          providers.GCP,
+				Region:            "europe-west9",
+				Count:             1,
+				ReplicationFactor: 1,
+				Spot:              true,
+			},
+			Specs: &resources.ComputeResourceSpecs{
+				VCPUs:           int32(2),
+				MemoryMb:        int32(7680),
+				HddStorage:      decimal.Zero,
+				SsdStorage:      decimal.Zero,
+				AvgCPUUse:       decimal.NewFromFloat(0.05),
+				AvgCPUUseSource: resources.SourceLabel,
+			},
+		},
+		"google_compute_instance.gpu": resources.ComputeResource{
+			Identification: &resources.ResourceIdentification{
+				Name:              "gpu",
+				Address:           "google_compute_instance.gpu",
+				ResourceType:      "google_compute_instance",
+				Provider:          providers.GCP,
+				Region:            "europe-west9",
+				Count:             1,
+				ReplicationFactor: 1,
+				Spot:              true,
+			},
+			Specs: &resources.ComputeResourceSpecs{
+				VCPUs:           int32(2),
+				MemoryMb:        int32(7680),
+				HddStorage:      decimal.Zero,
+				SsdStorage:      decimal.Zero,
+				GpuTypes:        []string{"nvidia-tesla-t4"},
+				AvgGPUUse:       decimal.NewFromFloat(0.8),
+				AvgGPUUseSource: resources.SourceAssumptions,
+			},
+		},
+	}
+	tfPlan, err := terraform.CarboniferPlan("test/terraform/planJson/utilization.json")
+	assert.NoError(t, err)
+	gotResources, err := plan.GetResources(viper.GetViper(), tfPlan)
+	assert.NoError(t, err)
+	assert.Equal(t, len(wantResources), len(gotResources))
+	for _, got := range gotResources {
+		assert.Equal(t, wantResources[got.GetAddress()], withoutProvenance(got))
+	}
+}
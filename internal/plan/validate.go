@@ -0,0 +1,422 @@
+package plan
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/carboniferio/carbonifer/internal/providers"
+	"github.com/carboniferio/carbonifer/internal/utils"
+	"github.com/pkg/errors"
+	"github.com/polkeli/yaml/v3"
+	"github.com/spf13/viper"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+// MappingIssue is an error found in a mappings file by ValidateMappings
+type MappingIssue struct {
+	File     string // Path of the file in the mappings directory, such as aws/ec2_asg.yaml
+	Line     int    `json:",omitempty"`
+	Location string `json:",omitempty"` // Such as compute_resource.aws_instance.properties.vCPUs[0].paths[0]
+	Message  string
+}
+
+func (i MappingIssue) String() string {
+	position := i.File
+	if i.Line > 0 {
+		position = fmt.Sprintf("%v:%v", i.File, i.Line)
+	}
+	if i.Location == "" {
+		return fmt.Sprintf("%v: %v", position, i.Message)
+	}
+	return fmt.Sprintf("%v: %v: %v", position, i.Location, i.Message)
+}
+
+// Units of sizes understood when reading resources
+var knownUnits = []string{"b", "kb", "mb", "gb", "tb", "pb"}
+
+var placeholderRegex = regexp.MustCompile(`\${([^}]+)}`)
+
+// unknownFieldRegex matches the errors of unknown keys of the yaml decoder, such as "line 44: field zone not found in type plan.Reference"
+var unknownFieldRegex = regexp.MustCompile(`^line (\d+): field (.+) not found in type [\w.]*?(\w+)$`)
+
+var lineRegex = regexp.MustCompile(`^(?:yaml: )?line (\d+): (.+)$`)
+
+// mappingFile is a mappings file parsed for validation
+type mappingFile struct {
+	name     string
+	root     *yaml.Node
+	mappings Mappings
+}
+
+// mappingValidator collects the issues of a mappings file
+type mappingValidator struct {
+	file    mappingFile
+	general map[providers.Provider]GeneralConfig
+	config  *viper.Viper
+	issues  []MappingIssue
+}
+
+// mappingScope is what placeholders of a resource mapping can refer to
+type mappingScope struct {
+	provider  providers.Provider
+	variables map[string]bool
+}
+
+// ValidateMappings checks the mappings of a directory, or of the mappings in use if dir is empty, and returns the issues found.
+// It checks the keys of the YAML files, compiles every jq path, placeholders resolved, and checks references, regular
+// expressions and units.
+func ValidateMappings(cfg *viper.Viper, dir string) ([]MappingIssue, error) {
+	var mappingsDir fs.FS
+	if dir == "" {
+		var err error
+		mappingsDir, err = mappingsFS(cfg)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		info, err := os.Stat(dir)
+		if err != nil {
+			return nil, errors.Wrap(err, "Cannot read mappings directory")
+		}
+		if !info.IsDir() {
+			return nil, errors.Errorf("Mappings path %v is not a directory", dir)
+		}
+		mappingsDir = os.DirFS(dir)
+	}
+
+	issues := []MappingIssue{}
+	files := []mappingFile{}
+	general := map[providers.Provider]GeneralConfig{}
+	folders, err := fs.ReadDir(mappingsDir, ".")
+	if err != nil {
+		return nil, err
+	}
+	for _, folder := range folders {
+		if !folder.IsDir() {
+			continue
+		}
+		entries, err := fs.ReadDir(mappingsDir, folder.Name())
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			if entry.IsDir() {
+				continue
+			}
+			name := path.Join(folder.Name(), entry.Name())
+			content, err := fs.ReadFile(mappingsDir, name)
+			if err != nil {
+				return nil, err
+			}
+			file, fileIssues := parseMappingFile(name, content)
+			issues = append(issues, fileIssues...)
+			if file == nil {
+				continue
+			}
+			files = append(files, *file)
+			if file.mappings.General != nil {
+				maps.Copy(general, *file.mappings.General)
+			}
+		}
+	}
+
+	for _, file := range files {
+		validator := mappingValidator{file: file, general: general, config: cfg}
+		validator.validate()
+		issues = append(issues, validator.issues...)
+	}
+	sort.SliceStable(issues, func(i, j int) bool {
+		if issues[i].File != issues[j].File {
+			return issues[i].File < issues[j].File
+		}
+		return issues[i].Line < issues[j].Line
+	})
+	return issues, nil
+}
+
+// parseMappingFile decodes a mappings file, reporting unknown keys and values of a wrong type.
+// The file is nil if it is not valid YAML.
+func parseMappingFile(name string, content []byte) (*mappingFile, []MappingIssue) {
+	issues := []MappingIssue{}
+	var root yaml.Node
+	if err := yaml.Unmarshal(content, &root); err != nil {
+		return nil, append(issues, newLineIssue(name, err.Error()))
+	}
+
+	var mappings Mappings
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	decoder.KnownFields(true)
+	err := decoder.Decode(&mappings)
+	if typeError, ok := err.(*yaml.TypeError); ok {
+		// Decoding goes on after a type error, mappings are set
+		for _, message := range typeError.Errors {
+			issues = append(issues, newLineIssue(name, message))
+		}
+	} else if err != nil && err.Error() != "EOF" {
+		return nil, append(issues, newLineIssue(name, err.Error()))
+	}
+	return &mappingFile{name: name, root: &root, mappings: mappings}, issues
+}
+
+// newLineIssue returns the issue of a yaml error message, prefixed by its line
+func newLineIssue(file string, message string) MappingIssue {
+	issue := MappingIssue{File: file, Message: message}
+	if match := unknownFieldRegex.FindStringSubmatch(message); match != nil {
+		issue.Line, _ = strconv.Atoi(match[1])
+		issue.Message = fmt.Sprintf("unknown key '%v' in %v", match[2], match[3])
+	} else if match := lineRegex.FindStringSubmatch(message); match != nil {
+		issue.Line, _ = strconv.Atoi(match[1])
+		issue.Message = match[2]
+	}
+	return issue
+}
+
+func (v *mappingValidator) validate() {
+	if v.file.mappings.General != nil {
+		for provider, general := range *v.file.mappings.General {
+			location := []interface{}{"general", provider.String()}
+			if general.JSONData != nil {
+				for key, file := range *general.JSONData {
+					if _, ok := file.(string); !ok {
+						v.report(append(location, "json_data", key), "json data file must be a file name, not %v", file)
+					}
+				}
+			}
+			if general.IgnoredResources != nil {
+				for i, pattern := range *general.IgnoredResources {
+					if _, err := regexp.Compile(pattern); err != nil {
+						v.report(append(location, "ignored_resources", i), "invalid regular expression: %v", err)
+					}
+				}
+			}
+		}
+	}
+	if v.file.mappings.ComputeResource != nil {
+		resourceTypes := maps.Keys(*v.file.mappings.ComputeResource)
+		sort.Strings(resourceTypes)
+		for _, resourceType := range resourceTypes {
+			v.validateResource(resourceType, (*v.file.mappings.ComputeResource)[resourceType])
+		}
+	}
+}
+
+func (v *mappingValidator) validateResource(resourceType string, mapping ResourceMapping) {
+	location := []interface{}{"compute_resource", resourceType}
+	providerName, _, _ := strings.Cut(resourceType, "_")
+	provider, err := parseProvider(providerName)
+	if err != nil {
+		v.report(location, "cannot get provider of resource type: %v", err)
+	}
+	if len(mapping.Paths) == 0 {
+		v.report(location, "no paths to select the resources")
+	}
+	if mapping.Properties == nil {
+		v.report(location, "no properties")
+	}
+
+	scope := mappingScope{provider: provider, variables: map[string]bool{}}
+	if mapping.Variables != nil && mapping.Variables.Properties != nil {
+		for name := range *mapping.Variables.Properties {
+			scope.variables[name] = true
+		}
+	}
+	for i, path := range mapping.Paths {
+		v.validateQuery(append(location, "paths", i), path, scope, false)
+	}
+	for i, path := range mapping.IgnoredPaths {
+		v.validateQuery(append(location, "ignored_paths", i), path, scope, false)
+	}
+	if mapping.Variables != nil {
+		v.validateProperties(append(location, "variables", "properties"), mapping.Variables.Properties, scope)
+	}
+	v.validateProperties(append(location, "properties"), mapping.Properties, scope)
+}
+
+func (v *mappingValidator) validateProperties(location []interface{}, properties *map[string][]PropertyDefinition, scope mappingScope) {
+	if properties == nil {
+		return
+	}
+	names := maps.Keys(*properties)
+	sort.Strings(names)
+	for _, name := range names {
+		for i, definition := range (*properties)[name] {
+			v.validateProperty(append(location, name, i), definition, scope)
+		}
+	}
+}
+
+func (v *mappingValidator) validateProperty(location []interface{}, definition PropertyDefinition, scope mappingScope) {
+	location = append([]interface{}{}, location...)
+	for i, path := range definition.Paths {
+		v.validateQuery(append(location, "paths", i), path, scope, false)
+	}
+	if definition.Unit != nil && !slices.Contains(knownUnits, strings.ToLower(*definition.Unit)) {
+		v.report(append(location, "unit"), "unknown unit '%v', must be one of %v", *definition.Unit, strings.Join(knownUnits, ", "))
+	}
+	if definition.Validator != nil {
+		v.validateQuery(append(location, "validator"), *definition.Validator, scope, false)
+	}
+	if definition.Regex != nil {
+		regex, err := regexp.Compile(definition.Regex.Pattern)
+		if err != nil {
+			v.report(append(location, "regex", "pattern"), "invalid regular expression: %v", err)
+		} else if definition.Regex.Group < 0 || definition.Regex.Group > regex.NumSubexp() {
+			v.report(append(location, "regex", "group"), "group %v not in regular expression '%v'", definition.Regex.Group, definition.Regex.Pattern)
+		}
+	}
+	if definition.Reference != nil {
+		v.validateReference(append(location, "reference"), *definition.Reference, scope)
+	}
+	if definition.Type != nil && *definition.Type == "list" && definition.Item == nil {
+		v.report(location, "list without item")
+	}
+	if definition.Item != nil {
+		for i, item := range *definition.Item {
+			itemLocation := append(append([]interface{}{}, location...), "item", i)
+			if len(item.Paths) == 0 {
+				v.report(itemLocation, "no paths to select the items")
+			}
+			for j, path := range item.Paths {
+				v.validateQuery(append(itemLocation, "paths", j), path, scope, false)
+			}
+			v.validateProperties(append(itemLocation, "properties"), item.Properties, scope)
+		}
+	}
+}
+
+func (v *mappingValidator) validateReference(location []interface{}, reference Reference, scope mappingScope) {
+	location = append([]interface{}{}, location...)
+	kinds := []string{}
+	if reference.JSONFile != "" {
+		kinds = append(kinds, "json_file")
+		general := v.general[scope.provider]
+		if general.JSONData == nil {
+			v.report(append(location, "json_file"), "json file '%v' not found, no general.json_data for provider %v", reference.JSONFile, scope.provider)
+		} else if _, ok := (*general.JSONData)[reference.JSONFile]; !ok {
+			v.report(append(location, "json_file"), "json file '%v' not found in general.json_data of provider %v", reference.JSONFile, scope.provider)
+		}
+	}
+	if reference.General != "" {
+		kinds = append(kinds, "general")
+		if reference.General != "disk_types" {
+			v.report(append(location, "general"), "unknown general reference '%v', must be disk_types", reference.General)
+		} else if v.general[scope.provider].DiskTypes == nil {
+			v.report(append(location, "general"), "no general.disk_types for provider %v", scope.provider)
+		}
+	}
+	if len(reference.Paths) > 0 {
+		kinds = append(kinds, "paths")
+		for i, path := range reference.Paths {
+			v.validateQuery(append(location, "paths", i), path, scope, true)
+		}
+	}
+	if len(kinds) > 1 {
+		v.report(location, "only one of %v is used", strings.Join(kinds, ", "))
+	}
+	if reference.Property != "" {
+		if len(kinds) == 0 {
+			v.report(append(location, "property"), "property without json_file or paths to read it from")
+		}
+		v.validateQuery(append(location, "property"), reference.Property, scope, false)
+	}
+}
+
+// validateQuery checks the placeholders of a jq query, then compiles it with placeholders resolved as not found.
+// ${key} is the value referenced, only set in paths of references.
+func (v *mappingValidator) validateQuery(location []interface{}, query string, scope mappingScope, withKey bool) {
+	for _, match := range placeholderRegex.FindAllStringSubmatch(query, -1) {
+		expression := match[1]
+		switch {
+		case strings.HasPrefix(expression, "this."):
+			if err := utils.CompileJSONQuery(strings.TrimPrefix(expression, "this")); err != nil {
+				v.report(location, "invalid placeholder %v: %v", match[0], err)
+			}
+		case strings.HasPrefix(expression, "config."):
+			if !v.config.IsSet(strings.TrimPrefix(expression, "config.")) {
+				v.report(location, "unknown config key in placeholder %v", match[0])
+			}
+		case expression == "key":
+			if !withKey {
+				v.report(location, "placeholder %v is only set in paths of references", match[0])
+			}
+		default:
+			if !scope.variables[expression] {
+				v.report(location, "unknown variable in placeholder %v", match[0])
+			}
+		}
+	}
+
+	// Placeholders not found are replaced the same way when reading resources
+	resolved := placeholderRegex.ReplaceAllString(query, ".not_found")
+	if err := utils.CompileJSONQuery(resolved); err != nil {
+		v.report(location, "invalid jq query: %v", err)
+	}
+}
+
+func (v *mappingValidator) report(location []interface{}, format string, args ...interface{}) {
+	v.issues = append(v.issues, MappingIssue{
+		File:     v.file.name,
+		Line:     nodeLine(v.file.root, location),
+		Location: formatLocation(location),
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// nodeLine returns the line of the yaml node at a location, or of its closest parent found
+func nodeLine(root *yaml.Node, location []interface{}) int {
+	node := root
+	if node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		node = node.Content[0]
+	}
+	line := node.Line
+	for _, segment := range location {
+		var next *yaml.Node
+		switch segment := segment.(type) {
+		case string:
+			if node.Kind == yaml.MappingNode {
+				for i := 0; i+1 < len(node.Content); i += 2 {
+					if node.Content[i].Value == segment {
+						next = node.Content[i+1]
+						line = node.Content[i].Line
+						break
+					}
+				}
+			}
+		case int:
+			if node.Kind == yaml.SequenceNode && segment < len(node.Content) {
+				next = node.Content[segment]
+			}
+		}
+		if next == nil {
+			return line
+		}
+		node = next
+		line = node.Line
+	}
+	return line
+}
+
+// formatLocation formats a location such as compute_resource.aws_instance.properties.vCPUs[0].paths[0]
+func formatLocation(location []interface{}) string {
+	var formatted strings.Builder
+	for _, segment := range location {
+		switch segment := segment.(type) {
+		case int:
+			fmt.Fprintf(&formatted, "[%v]", segment)
+		default:
+			if formatted.Len() > 0 {
+				formatted.WriteString(".")
+			}
+			fmt.Fprintf(&formatted, "%v", segment)
+		}
+	}
+	return formatted.String()
+}
@@ -1,7 +1,9 @@
 package providers
 
 import (
+	"sort"
 	"strings"
+	"sync"
 
 	"github.com/carboniferio/carbonifer/internal/data"
 	"github.com/shopspring/decimal"
@@ -9,7 +11,9 @@ import (
 	"github.com/yunabe/easycsv"
 )
 
-var wattPerGPU map[string]GPUWatt
+// wattPerGPU are the watts of the GPUs, per data directory
+var wattPerGPU = map[string]map[string]GPUWatt{}
+var wattPerGPUMutex sync.Mutex
 
 // GPUWatt is the struct that contains the min and max watts of a GPU
 type GPUWatt struct {
@@ -24,30 +28,61 @@ type gpuWattCSV struct {
 	MaxWatts float64 `name:"max watts"`
 }
 
-// GetGPUWatt returns the min and max watts of a GPU
-func GetGPUWatt(gpuName string) GPUWatt {
-	// Source: https://www.cloudcarbonfootprint.org/docs/methodology#appendix-iii-gpus-and-minmax-watts
+// GetGPUWatt returns the min and max watts of a GPU, zero if the GPU is unknown
+func GetGPUWatt(dataPath string, gpuName string) (GPUWatt, error) {
 	log.Debugf("  Getting info for GPU type: %v", gpuName)
-	if wattPerGPU == nil {
-		// Read the CSV records
-		var records []gpuWattCSV
-		gpuPowerDataFile := data.ReadDataFile("gpu_watt.csv")
-		log.Debugf("  reading gpu power data from: %v", gpuPowerDataFile)
-		if err := easycsv.NewReader(strings.NewReader(string(gpuPowerDataFile))).ReadAll(&records); err != nil {
-			log.Fatal(err)
-		}
+	gpuWattsByName, err := loadGPUWatts(dataPath)
+	if err != nil {
+		return GPUWatt{}, err
+	}
+	return gpuWattsByName[strings.ToLower(gpuName)], nil
+}
+
+// GetGPUWatts returns the min and max watts of all the known GPUs, sorted by name
+func GetGPUWatts(dataPath string) ([]GPUWatt, error) {
+	gpuWattsByName, err := loadGPUWatts(dataPath)
+	if err != nil {
+		return nil, err
+	}
+	gpuWatts := make([]GPUWatt, 0, len(gpuWattsByName))
+	for _, gpuWatt := range gpuWattsByName {
+		gpuWatts = append(gpuWatts, gpuWatt)
+	}
+	sort.Slice(gpuWatts, func(i, j int) bool {
+		return gpuWatts[i].Name < gpuWatts[j].Name
+	})
+	return gpuWatts, nil
+}
+
+func loadGPUWatts(dataPath string) (map[string]GPUWatt, error) {
+	// Source: https://www.cloudcarbonfootprint.org/docs/methodology#appendix-iii-gpus-and-minmax-watts
+	wattPerGPUMutex.Lock()
+	defer wattPerGPUMutex.Unlock()
+	if gpuWatts, ok := wattPerGPU[dataPath]; ok {
+		return gpuWatts, nil
+	}
+	// Read the CSV records
+	var records []gpuWattCSV
+	gpuPowerDataFile, err := data.ReadDataFile(dataPath, "gpu_watt.csv")
+	if err != nil {
+		return nil, err
+	}
+	log.Debugf("  reading gpu power data from: %v", gpuPowerDataFile)
+	if err := easycsv.NewReader(strings.NewReader(string(gpuPowerDataFile))).ReadAll(&records); err != nil {
+		return nil, &data.MalformedDataError{File: "gpu_watt.csv", Err: err}
+	}
 
-		// Create a map to store the data
-		wattPerGPU = make(map[string]GPUWatt)
+	// Create a map to store the data
+	gpuWatts := make(map[string]GPUWatt)
 
-		// Iterate over the records and add them to the map
-		for _, record := range records {
-			wattPerGPU[strings.ToLower(record.Name)] = GPUWatt{
-				Name:     record.Name,
-				MinWatts: decimal.NewFromFloat(record.MinWatts),
-				MaxWatts: decimal.NewFromFloat(record.MaxWatts),
-			}
+	// Iterate over the records and add them to the map
+	for _, record := range records {
+		gpuWatts[strings.ToLower(record.Name)] = GPUWatt{
+			Name:     record.Name,
+			MinWatts: decimal.NewFromFloat(record.MinWatts),
+			MaxWatts: decimal.NewFromFloat(record.MaxWatts),
 		}
 	}
-	return wattPerGPU[strings.ToLower(gpuName)]
+	wattPerGPU[dataPath] = gpuWatts
+	return gpuWatts, nil
 }
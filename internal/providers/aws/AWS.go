@@ -2,8 +2,11 @@ package aws
 
 import (
 	"encoding/json"
+	"sort"
+	"sync"
 
 	"github.com/carboniferio/carbonifer/internal/data"
+	"github.com/carboniferio/carbonifer/internal/providers"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -12,9 +15,36 @@ type InstanceType struct {
 	InstanceType    string          `json:"InstanceType"`
 	VCPU            int32           `json:"VCPU"`
 	MemoryMb        int32           `json:"MemoryMb"`
+	GPUs            []string        `json:"GPUs"` // One item per GPU
 	InstanceStorage InstanceStorage `json:"InstanceStorage"`
 }
 
+// gpuTypes are the names in gpu_watt.csv of the GPUs of AWS instance types
+var gpuTypes = map[string]string{
+	"A100":            "nvidia-tesla-a100",
+	"A10G":            "nvidia-a10g",
+	"K520":            "nvidia-k520",
+	"K80":             "nvidia-tesla-k80",
+	"M60":             "nvidia-tesla-m60",
+	"Radeon Pro V520": "amd-radeon-pro-v520",
+	"T4":              "nvidia-tesla-t4",
+	"T4g":             "nvidia-t4",
+	"V100":            "nvidia-tesla-v100",
+}
+
+// GPUTypes returns the GPUs of the instance type, named as in gpu_watt.csv when known
+func (i InstanceType) GPUTypes() []string {
+	var types []string
+	for _, gpu := range i.GPUs {
+		if gpuType, ok := gpuTypes[gpu]; ok {
+			types = append(types, gpuType)
+		} else {
+			types = append(types, gpu)
+		}
+	}
+	return types
+}
+
 // InstanceStorage is a struct that contains the information of the storage of an AWS instance type
 type InstanceStorage struct {
 	SizePerDiskGB int64 `json:"SizePerDiskGB"`
@@ -22,18 +52,91 @@ type InstanceStorage struct {
 	Type          string
 }
 
-var awsInstanceTypes map[string]InstanceType
+// awsInstanceTypes are the instance types, per data directory
+var awsInstanceTypes = map[string]map[string]InstanceType{}
+var awsInstanceTypesMutex sync.Mutex
 
 // GetAWSInstanceType returns the information of an AWS instance type
-func GetAWSInstanceType(instanceTypeStr string) InstanceType {
+func GetAWSInstanceType(dataPath string, instanceTypeStr string) (InstanceType, error) {
 	log.Debugf("  Getting info for AWS machine type: %v", instanceTypeStr)
-	if awsInstanceTypes == nil {
-		byteValue := data.ReadDataFile("aws_instances.json")
-		err := json.Unmarshal([]byte(byteValue), &awsInstanceTypes)
-		if err != nil {
-			log.Fatal(err)
+	instanceTypes, err := loadInstanceTypes(dataPath)
+	if err != nil {
+		return InstanceType{}, err
+	}
+
+	instanceType, ok := instanceTypes[instanceTypeStr]
+	if !ok {
+		return InstanceType{}, &providers.UnknownInstanceTypeError{
+			Provider:     providers.AWS,
+			InstanceType: instanceTypeStr,
+			Suggestions:  providers.SuggestInstanceTypes(instanceTypeStr, instanceTypeSpecs(instanceTypes)),
+		}
+	}
+	return instanceType, nil
+}
+
+// GetAWSFamilyAverageInstanceType returns an unknown AWS instance type estimated from the average memory per vCPU of
+// its family, such as m5 for m5.3xlarge
+func GetAWSFamilyAverageInstanceType(dataPath string, instanceTypeStr string) (InstanceType, error) {
+	instanceTypes, err := loadInstanceTypes(dataPath)
+	if err != nil {
+		return InstanceType{}, err
+	}
+	specs, ok := providers.FamilyAverageInstanceType(instanceTypeStr, instanceTypeSpecs(instanceTypes))
+	if !ok {
+		return InstanceType{}, &providers.UnknownInstanceTypeError{
+			Provider:     providers.AWS,
+			InstanceType: instanceTypeStr,
+			Suggestions:  providers.SuggestInstanceTypes(instanceTypeStr, instanceTypeSpecs(instanceTypes)),
 		}
 	}
+	return InstanceType{
+		InstanceType: specs.Name,
+		VCPU:         specs.VCPUs,
+		MemoryMb:     specs.MemoryMb,
+	}, nil
+}
+
+// instanceTypeSpecs returns the vCPUs and memory of instance types
+func instanceTypeSpecs(instanceTypes map[string]InstanceType) []providers.InstanceSpecs {
+	specs := make([]providers.InstanceSpecs, 0, len(instanceTypes))
+	for _, instanceType := range instanceTypes {
+		specs = append(specs, providers.InstanceSpecs{Name: instanceType.InstanceType, VCPUs: instanceType.VCPU, MemoryMb: instanceType.MemoryMb})
+	}
+	return specs
+}
+
+// GetAWSInstanceTypes returns the information of all the AWS instance types, sorted by name
+func GetAWSInstanceTypes(dataPath string) ([]InstanceType, error) {
+	instanceTypesByName, err := loadInstanceTypes(dataPath)
+	if err != nil {
+		return nil, err
+	}
+	instanceTypes := make([]InstanceType, 0, len(instanceTypesByName))
+	for _, instanceType := range instanceTypesByName {
+		instanceTypes = append(instanceTypes, instanceType)
+	}
+	sort.Slice(instanceTypes, func(i, j int) bool {
+		return instanceTypes[i].InstanceType < instanceTypes[j].InstanceType
+	})
+	return instanceTypes, nil
+}
 
-	return awsInstanceTypes[instanceTypeStr]
+func loadInstanceTypes(dataPath string) (map[string]InstanceType, error) {
+	awsInstanceTypesMutex.Lock()
+	defer awsInstanceTypesMutex.Unlock()
+	if instanceTypes, ok := awsInstanceTypes[dataPath]; ok {
+		return instanceTypes, nil
+	}
+	byteValue, err := data.ReadDataFile(dataPath, "aws_instances.json")
+	if err != nil {
+		return nil, err
+	}
+	var instanceTypes map[string]InstanceType
+	err = json.Unmarshal([]byte(byteValue), &instanceTypes)
+	if err != nil {
+		return nil, &data.MalformedDataError{File: "aws_instances.json", Err: err}
+	}
+	awsInstanceTypes[dataPath] = instanceTypes
+	return instanceTypes, nil
 }
@@ -1,10 +1,13 @@
 package aws
 
 import (
+	"errors"
 	"reflect"
 	"testing"
 
+	"github.com/carboniferio/carbonifer/internal/providers"
 	_ "github.com/carboniferio/carbonifer/internal/testutils"
+	"github.com/spf13/viper"
 )
 
 func TestGetAWSInstanceType(t *testing.T) {
@@ -23,6 +26,7 @@ func TestGetAWSInstanceType(t *testing.T) {
 				InstanceType: "c5d.12xlarge",
 				VCPU:         48,
 				MemoryMb:     96 * 1024,
+				GPUs:         []string{},
 				InstanceStorage: InstanceStorage{
 					SizePerDiskGB: 900,
 					Count:         2,
@@ -30,12 +34,89 @@ func TestGetAWSInstanceType(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "p3.8xlarge",
+			args: args{instanceTypeStr: "p3.8xlarge"},
+			want: InstanceType{
+				InstanceType: "p3.8xlarge",
+				VCPU:         32,
+				MemoryMb:     244 * 1024,
+				GPUs:         []string{"V100", "V100", "V100", "V100"},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := GetAWSInstanceType(tt.args.instanceTypeStr); !reflect.DeepEqual(got, tt.want) {
+			got, err := GetAWSInstanceType(viper.GetString("data.path"), tt.args.instanceTypeStr)
+			if err != nil {
+				t.Fatalf("GetAWSInstanceType() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("GetAWSInstanceType() = %v, want %v", got, tt.want)
 			}
 		})
 	}
 }
+
+func TestGetAWSInstanceType_Unknown(t *testing.T) {
+	_, err := GetAWSInstanceType(viper.GetString("data.path"), "x9.unknown")
+	var unknownInstanceTypeError *providers.UnknownInstanceTypeError
+	if !errors.As(err, &unknownInstanceTypeError) {
+		t.Fatalf("GetAWSInstanceType() error = %v, want UnknownInstanceTypeError", err)
+	}
+	if unknownInstanceTypeError.InstanceType != "x9.unknown" {
+		t.Errorf("InstanceType = %v, want x9.unknown", unknownInstanceTypeError.InstanceType)
+	}
+}
+
+func TestGetAWSInstanceType_Suggestions(t *testing.T) {
+	_, err := GetAWSInstanceType(viper.GetString("data.path"), "m5.larg")
+	var unknownInstanceTypeError *providers.UnknownInstanceTypeError
+	if !errors.As(err, &unknownInstanceTypeError) {
+		t.Fatalf("GetAWSInstanceType() error = %v, want UnknownInstanceTypeError", err)
+	}
+	want := []string{"m5.large", "m5.xlarge", "m5.2xlarge"}
+	if !reflect.DeepEqual(unknownInstanceTypeError.Suggestions, want) {
+		t.Errorf("Suggestions = %v, want %v", unknownInstanceTypeError.Suggestions, want)
+	}
+}
+
+func TestGetAWSFamilyAverageInstanceType(t *testing.T) {
+	got, err := GetAWSFamilyAverageInstanceType(viper.GetString("data.path"), "m5.3xlarge")
+	if err != nil {
+		t.Fatalf("GetAWSFamilyAverageInstanceType() error = %v", err)
+	}
+	want := InstanceType{InstanceType: "m5.3xlarge", VCPU: 12, MemoryMb: 49152}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetAWSFamilyAverageInstanceType() = %v, want %v", got, want)
+	}
+
+	_, err = GetAWSFamilyAverageInstanceType(viper.GetString("data.path"), "x9.large")
+	var unknownInstanceTypeError *providers.UnknownInstanceTypeError
+	if !errors.As(err, &unknownInstanceTypeError) {
+		t.Fatalf("GetAWSFamilyAverageInstanceType() error = %v, want UnknownInstanceTypeError", err)
+	}
+}
+
+func TestInstanceType_GPUTypes(t *testing.T) {
+	instanceType := InstanceType{GPUs: []string{"T4", "T4", "Gaudi HL-205"}}
+	want := []string{"nvidia-tesla-t4", "nvidia-tesla-t4", "Gaudi HL-205"}
+	if got := instanceType.GPUTypes(); !reflect.DeepEqual(got, want) {
+		t.Errorf("GPUTypes() = %v, want %v", got, want)
+	}
+}
+
+func TestGetAWSInstanceTypes(t *testing.T) {
+	instanceTypes, err := GetAWSInstanceTypes(viper.GetString("data.path"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(instanceTypes) == 0 {
+		t.Fatal("GetAWSInstanceTypes() returned no instance type")
+	}
+	for i := 1; i < len(instanceTypes); i++ {
+		if instanceTypes[i-1].InstanceType >= instanceTypes[i].InstanceType {
+			t.Errorf("GetAWSInstanceTypes() not sorted: %v before %v", instanceTypes[i-1].InstanceType, instanceTypes[i].InstanceType)
+		}
+	}
+}
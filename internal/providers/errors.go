@@ -0,0 +1,33 @@
+package providers
+
+import (
+	"fmt"
+	"strings"
+)
+
+// UnknownRegionError is an error that occurs when a region is not in the data files of its provider
+type UnknownRegionError struct {
+	Provider Provider
+	Region   string
+}
+
+func (e *UnknownRegionError) Error() string {
+	if e.Region == "" {
+		return fmt.Sprintf("Region of %v resource cannot be empty", e.Provider)
+	}
+	return fmt.Sprintf("Unknown %v region: '%v'", e.Provider, e.Region)
+}
+
+// UnknownInstanceTypeError is an error that occurs when an instance type is not in the data files of its provider
+type UnknownInstanceTypeError struct {
+	Provider     Provider
+	InstanceType string
+	Suggestions  []string // known instance types with a similar name, closest first
+}
+
+func (e *UnknownInstanceTypeError) Error() string {
+	if len(e.Suggestions) == 0 {
+		return fmt.Sprintf("Unknown %v instance type: '%v'", e.Provider, e.InstanceType)
+	}
+	return fmt.Sprintf("Unknown %v instance type: '%v', did you mean '%v'?", e.Provider, e.InstanceType, strings.Join(e.Suggestions, "', '"))
+}
@@ -3,10 +3,13 @@ package gcp
 import (
 	"encoding/json"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/carboniferio/carbonifer/internal/data"
+	"github.com/carboniferio/carbonifer/internal/providers"
 	"github.com/shopspring/decimal"
 	log "github.com/sirupsen/logrus"
 
@@ -30,6 +33,14 @@ type SQLTier struct {
 	DiskQuotaGB int64  `json:"DiskQuotaGB"`
 }
 
+// RedisTier is a struct that contains the information of a Memorystore for Redis capacity tier
+type RedisTier struct {
+	Name        string `json:"name"`
+	MinMemoryGb int64  `json:"minMemoryGb"`
+	MaxMemoryGb int64  `json:"maxMemoryGb"`
+	Vcpus       int64  `json:"vcpus"`
+}
+
 // CPUWatt is a struct that contains the information of a GCP CPU type
 type CPUWatt struct {
 	Architecture        string
@@ -38,12 +49,14 @@ type CPUWatt struct {
 	GridCarbonIntensity decimal.Decimal
 }
 
-var gcpInstanceTypes map[string]MachineType
-var gcpWattPerCPU map[string]CPUWatt
-var gcpSQLTiers map[string]SQLTier
+// Data files read, per data directory
+var gcpInstanceTypes = map[string]map[string]MachineType{}
+var gcpWattPerCPU = map[string]map[string]CPUWatt{}
+var gcpSQLTiers = map[string]map[string]SQLTier{}
+var gcpDataMutex sync.Mutex
 
 // GetGCPMachineType returns the information of a GCP instance type
-func GetGCPMachineType(machineTypeStr string, zone string) MachineType {
+func GetGCPMachineType(dataPath string, machineTypeStr string, zone string) (MachineType, error) {
 	log.Debugf("  Getting info for GCP machine type: %v", machineTypeStr)
 	// Custom format is custom-<number_cpus>-<ram_mb>
 	customMachineRegex := regexp.MustCompile(`custom-(?P<vcpus>\d+)-(?P<mem>\d+)(-ext)?`)
@@ -51,32 +64,102 @@ func GetGCPMachineType(machineTypeStr string, zone string) MachineType {
 		log.Debugf("  custom machine: %v", machineTypeStr)
 		customValues := customMachineRegex.FindAllStringSubmatch(machineTypeStr, -1)[0]
 		if len(customValues) < 3 {
-			log.Fatalf("GCP Custom machine name malformed : %v", machineTypeStr)
+			return MachineType{}, &providers.UnknownInstanceTypeError{Provider: providers.GCP, InstanceType: machineTypeStr}
 		}
 		vCPUs, err := strconv.Atoi(customValues[1])
 		if err != nil {
-			log.Fatalf(err.Error())
+			return MachineType{}, &providers.UnknownInstanceTypeError{Provider: providers.GCP, InstanceType: machineTypeStr}
 		}
 		ram, err := strconv.Atoi(customValues[2])
 		if err != nil {
-			log.Fatalf(err.Error())
+			return MachineType{}, &providers.UnknownInstanceTypeError{Provider: providers.GCP, InstanceType: machineTypeStr}
 		}
 		return MachineType{
 			Name:     machineTypeStr,
 			Vcpus:    int32(vCPUs),
 			MemoryMb: int32(ram),
+		}, nil
+	}
+	machineTypes, err := loadMachineTypes(dataPath)
+	if err != nil {
+		return MachineType{}, err
+	}
+
+	machineType, ok := machineTypes[machineTypeStr]
+	if !ok {
+		return MachineType{}, &providers.UnknownInstanceTypeError{
+			Provider:     providers.GCP,
+			InstanceType: machineTypeStr,
+			Suggestions:  providers.SuggestInstanceTypes(machineTypeStr, machineTypeSpecs(machineTypes)),
 		}
 	}
-	if gcpInstanceTypes == nil {
-		byteValue := data.ReadDataFile("gcp_instances.json")
-		err := json.Unmarshal([]byte(byteValue), &gcpInstanceTypes)
-		if err != nil {
-			log.Fatal(err)
+	return machineType, nil
+}
+
+// GetGCPFamilyAverageMachineType returns an unknown GCP machine type estimated from the average memory per vCPU of its
+// family, such as n2-standard for n2-standard-6
+func GetGCPFamilyAverageMachineType(dataPath string, machineTypeStr string) (MachineType, error) {
+	machineTypes, err := loadMachineTypes(dataPath)
+	if err != nil {
+		return MachineType{}, err
+	}
+	specs, ok := providers.FamilyAverageInstanceType(machineTypeStr, machineTypeSpecs(machineTypes))
+	if !ok {
+		return MachineType{}, &providers.UnknownInstanceTypeError{
+			Provider:     providers.GCP,
+			InstanceType: machineTypeStr,
+			Suggestions:  providers.SuggestInstanceTypes(machineTypeStr, machineTypeSpecs(machineTypes)),
 		}
 	}
+	return MachineType{
+		Name:     specs.Name,
+		Vcpus:    specs.VCPUs,
+		MemoryMb: specs.MemoryMb,
+	}, nil
+}
+
+// machineTypeSpecs returns the vCPUs and memory of machine types
+func machineTypeSpecs(machineTypes map[string]MachineType) []providers.InstanceSpecs {
+	specs := make([]providers.InstanceSpecs, 0, len(machineTypes))
+	for _, machineType := range machineTypes {
+		specs = append(specs, providers.InstanceSpecs{Name: machineType.Name, VCPUs: machineType.Vcpus, MemoryMb: machineType.MemoryMb})
+	}
+	return specs
+}
 
-	return gcpInstanceTypes[machineTypeStr]
+// GetGCPMachineTypes returns the information of all the GCP machine types, sorted by name
+func GetGCPMachineTypes(dataPath string) ([]MachineType, error) {
+	machineTypesByName, err := loadMachineTypes(dataPath)
+	if err != nil {
+		return nil, err
+	}
+	machineTypes := make([]MachineType, 0, len(machineTypesByName))
+	for _, machineType := range machineTypesByName {
+		machineTypes = append(machineTypes, machineType)
+	}
+	sort.Slice(machineTypes, func(i, j int) bool {
+		return machineTypes[i].Name < machineTypes[j].Name
+	})
+	return machineTypes, nil
+}
 
+func loadMachineTypes(dataPath string) (map[string]MachineType, error) {
+	gcpDataMutex.Lock()
+	defer gcpDataMutex.Unlock()
+	if machineTypes, ok := gcpInstanceTypes[dataPath]; ok {
+		return machineTypes, nil
+	}
+	byteValue, err := data.ReadDataFile(dataPath, "gcp_instances.json")
+	if err != nil {
+		return nil, err
+	}
+	var machineTypes map[string]MachineType
+	err = json.Unmarshal([]byte(byteValue), &machineTypes)
+	if err != nil {
+		return nil, &data.MalformedDataError{File: "gcp_instances.json", Err: err}
+	}
+	gcpInstanceTypes[dataPath] = machineTypes
+	return machineTypes, nil
 }
 
 type cpuWattCSV struct {
@@ -86,36 +169,63 @@ type cpuWattCSV struct {
 	GridCarbonIntensity float64 `name:"GB/Chip"`
 }
 
+// cpuArchitectures are the architectures in gcp_watt_cpu.csv of CPU platforms named otherwise in gcp_instances.json or terraform
+var cpuArchitectures = map[string]string{
+	"amd epyc rome":  "epyc 2nd gen",
+	"amd rome":       "epyc 2nd gen",
+	"amd epyc milan": "epyc 3rd gen",
+	"amd milan":      "epyc 3rd gen",
+}
+
 // Source: https://github.com/cloud-carbon-footprint/cloud-carbon-coefficients/blob/5fcb96101c6f28dac5060f8794bca5d4da6c72d8/output/coefficients-gcp-use.csv
-// GetCPUWatt returns the min and max watts of a CPU
-func GetCPUWatt(cpu string) CPUWatt {
+// GetCPUWatt returns the min and max watts of a CPU, such as "Skylake", "Intel Skylake" or "AMD Milan", zero if the CPU is unknown
+func GetCPUWatt(dataPath string, cpu string) (CPUWatt, error) {
 	log.Debugf("  Getting info for GCP CPU type: %v", cpu)
-	if gcpWattPerCPU == nil {
-		// Read the CSV records
-		var records []cpuWattCSV
-		fileContents := data.ReadDataFile("gcp_watt_cpu.csv")
-		if err := easycsv.NewReader(strings.NewReader(string(fileContents))).ReadAll(&records); err != nil {
-			log.Fatal(err)
-		}
+	cpuWatts, err := loadCPUWatts(dataPath)
+	if err != nil {
+		return CPUWatt{}, err
+	}
+	architecture := strings.TrimPrefix(strings.ToLower(cpu), "intel ")
+	if alias, ok := cpuArchitectures[architecture]; ok {
+		architecture = alias
+	}
+	return cpuWatts[architecture], nil
+}
+
+func loadCPUWatts(dataPath string) (map[string]CPUWatt, error) {
+	gcpDataMutex.Lock()
+	defer gcpDataMutex.Unlock()
+	if cpuWatts, ok := gcpWattPerCPU[dataPath]; ok {
+		return cpuWatts, nil
+	}
+	// Read the CSV records
+	var records []cpuWattCSV
+	fileContents, err := data.ReadDataFile(dataPath, "gcp_watt_cpu.csv")
+	if err != nil {
+		return nil, err
+	}
+	if err := easycsv.NewReader(strings.NewReader(string(fileContents))).ReadAll(&records); err != nil {
+		return nil, &data.MalformedDataError{File: "gcp_watt_cpu.csv", Err: err}
+	}
+
+	// Create a map to store the data
+	cpuWatts := make(map[string]CPUWatt)
 
-		// Create a map to store the data
-		gcpWattPerCPU = make(map[string]CPUWatt)
-
-		// Iterate over the records and add them to the map
-		for _, record := range records {
-			gcpWattPerCPU[strings.ToLower(record.Architecture)] = CPUWatt{
-				Architecture:        record.Architecture,
-				MinWatts:            decimal.NewFromFloat(record.MinWatts),
-				MaxWatts:            decimal.NewFromFloat(record.MaxWatts),
-				GridCarbonIntensity: decimal.NewFromFloat(record.GridCarbonIntensity),
-			}
+	// Iterate over the records and add them to the map
+	for _, record := range records {
+		cpuWatts[strings.ToLower(record.Architecture)] = CPUWatt{
+			Architecture:        record.Architecture,
+			MinWatts:            decimal.NewFromFloat(record.MinWatts),
+			MaxWatts:            decimal.NewFromFloat(record.MaxWatts),
+			GridCarbonIntensity: decimal.NewFromFloat(record.GridCarbonIntensity),
 		}
 	}
-	return gcpWattPerCPU[strings.ToLower(cpu)]
+	gcpWattPerCPU[dataPath] = cpuWatts
+	return cpuWatts, nil
 }
 
 // GetGCPSQLTier returns the information of a GCP SQL tier
-func GetGCPSQLTier(tierName string) SQLTier {
+func GetGCPSQLTier(dataPath string, tierName string) (SQLTier, error) {
 	log.Debugf("  Getting info for GCP SQL tier: %v", tierName)
 	// Custom format db-custom-<number_cpus>-<ram_mb>
 	customTierRegex := regexp.MustCompile(`db-custom-(?P<vcpus>\d+)-(?P<mem>\d+)`)
@@ -123,30 +233,49 @@ func GetGCPSQLTier(tierName string) SQLTier {
 		log.Debugf("  custom SQL Tier: %v", tierName)
 		customValues := customTierRegex.FindAllStringSubmatch(tierName, -1)[0]
 		if len(customValues) < 3 {
-			log.Fatalf("GCP Custom tier name malformed : %v", tierName)
+			return SQLTier{}, &providers.UnknownInstanceTypeError{Provider: providers.GCP, InstanceType: tierName}
 		}
 		vCPUs, err := strconv.Atoi(customValues[1])
 		if err != nil {
-			log.Fatalf(err.Error())
+			return SQLTier{}, &providers.UnknownInstanceTypeError{Provider: providers.GCP, InstanceType: tierName}
 		}
 		ram, err := strconv.Atoi(customValues[2])
 		if err != nil {
-			log.Fatalf(err.Error())
+			return SQLTier{}, &providers.UnknownInstanceTypeError{Provider: providers.GCP, InstanceType: tierName}
 		}
 		return SQLTier{
 			Name:     tierName,
 			Vcpus:    int64(vCPUs),
 			MemoryMb: int64(ram),
-		}
+		}, nil
 	}
-	if gcpSQLTiers == nil {
-		byteValue := data.ReadDataFile("gcp_sql_tiers.json")
-		err := json.Unmarshal([]byte(byteValue), &gcpSQLTiers)
-		if err != nil {
-			log.Fatal(err)
-		}
+	sqlTiers, err := loadSQLTiers(dataPath)
+	if err != nil {
+		return SQLTier{}, err
 	}
 
-	return gcpSQLTiers[tierName]
+	sqlTier, ok := sqlTiers[tierName]
+	if !ok {
+		return SQLTier{}, &providers.UnknownInstanceTypeError{Provider: providers.GCP, InstanceType: tierName}
+	}
+	return sqlTier, nil
+}
 
+func loadSQLTiers(dataPath string) (map[string]SQLTier, error) {
+	gcpDataMutex.Lock()
+	defer gcpDataMutex.Unlock()
+	if sqlTiers, ok := gcpSQLTiers[dataPath]; ok {
+		return sqlTiers, nil
+	}
+	byteValue, err := data.ReadDataFile(dataPath, "gcp_sql_tiers.json")
+	if err != nil {
+		return nil, err
+	}
+	var sqlTiers map[string]SQLTier
+	err = json.Unmarshal([]byte(byteValue), &sqlTiers)
+	if err != nil {
+		return nil, &data.MalformedDataError{File: "gcp_sql_tiers.json", Err: err}
+	}
+	gcpSQLTiers[dataPath] = sqlTiers
+	return sqlTiers, nil
 }
@@ -3,8 +3,10 @@ package gcp
 import (
 	"testing"
 
+	"github.com/carboniferio/carbonifer/internal/providers"
 	_ "github.com/carboniferio/carbonifer/internal/testutils"
 	"github.com/shopspring/decimal"
+	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -44,14 +46,51 @@ func TestGetGCPMachineType(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := GetGCPMachineType(tt.args.machineTypeStr, tt.args.zone)
+			got, err := GetGCPMachineType(viper.GetString("data.path"), tt.args.machineTypeStr, tt.args.zone)
+			assert.NoError(t, err)
 			assert.Equal(t, got, tt.want)
 		})
 	}
 }
 
+func TestGetGCPMachineType_Unknown(t *testing.T) {
+	_, err := GetGCPMachineType(viper.GetString("data.path"), "e9-unknown-2", "europe-west9-a")
+	var unknownInstanceTypeError *providers.UnknownInstanceTypeError
+	assert.ErrorAs(t, err, &unknownInstanceTypeError)
+	assert.Equal(t, "e9-unknown-2", unknownInstanceTypeError.InstanceType)
+}
+
+func TestGetGCPMachineType_Suggestions(t *testing.T) {
+	_, err := GetGCPMachineType(viper.GetString("data.path"), "n1-standard-4", "europe-west9-a")
+	var unknownInstanceTypeError *providers.UnknownInstanceTypeError
+	assert.ErrorAs(t, err, &unknownInstanceTypeError)
+	assert.Equal(t, []string{"n1-standard-2", "c2-standard-4", "n2-standard-2"}, unknownInstanceTypeError.Suggestions)
+	assert.Equal(t, "Unknown GCP instance type: 'n1-standard-4', did you mean 'n1-standard-2', 'c2-standard-4', 'n2-standard-2'?", err.Error())
+}
+
+func TestGetGCPFamilyAverageMachineType(t *testing.T) {
+	got, err := GetGCPFamilyAverageMachineType(viper.GetString("data.path"), "n1-standard-4")
+	assert.NoError(t, err)
+	assert.Equal(t, MachineType{Name: "n1-standard-4", Vcpus: 4, MemoryMb: 15360}, got)
+
+	_, err = GetGCPFamilyAverageMachineType(viper.GetString("data.path"), "e9-unknown-2")
+	var unknownInstanceTypeError *providers.UnknownInstanceTypeError
+	assert.ErrorAs(t, err, &unknownInstanceTypeError)
+}
+
+func TestGetGCPMachineTypes(t *testing.T) {
+	machineTypes, err := GetGCPMachineTypes(viper.GetString("data.path"))
+	assert.NoError(t, err)
+	names := []string{}
+	for _, machineType := range machineTypes {
+		names = append(names, machineType.Name)
+	}
+	assert.Equal(t, []string{"a2-highgpu-1g", "c2-standard-4", "e2-standard-2", "n1-standard-2", "n2-standard-2", "n2d-highcpu-2"}, names)
+}
+
 func TestGetCPUWatt(t *testing.T) {
-	got := GetCPUWatt("Skylake")
+	got, err := GetCPUWatt(viper.GetString("data.path"), "Skylake")
+	assert.NoError(t, err)
 	want := CPUWatt{
 		Architecture:        "Skylake",
 		MinWatts:            decimal.NewFromFloat(0.6446044454253452),
@@ -60,3 +99,14 @@ func TestGetCPUWatt(t *testing.T) {
 	}
 	assert.Equal(t, got, want)
 }
+
+func TestGetCPUWatt_Aliases(t *testing.T) {
+	for _, cpu := range []string{"Intel Cascade Lake", "AMD EPYC Milan", "AMD Milan"} {
+		got, err := GetCPUWatt(viper.GetString("data.path"), cpu)
+		assert.NoError(t, err)
+		assert.NotEmpty(t, got.Architecture, cpu)
+	}
+	got, err := GetCPUWatt(viper.GetString("data.path"), "AMD EPYC Rome")
+	assert.NoError(t, err)
+	assert.Equal(t, "EPYC 2nd Gen", got.Architecture)
+}
@@ -0,0 +1,164 @@
+package providers
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// maxSuggestions is the number of known instance types suggested for an unknown one
+const maxSuggestions = 3
+
+// InstanceSpecs are the vCPUs and memory of a known instance type, to suggest and estimate unknown ones
+type InstanceSpecs struct {
+	Name     string
+	VCPUs    int32
+	MemoryMb int32
+}
+
+// InstanceFamily returns the family of an instance type, its name without the size: n2-standard for n2-standard-4,
+// t3 for t3.micro
+func InstanceFamily(instanceType string) string {
+	index := strings.LastIndexAny(instanceType, "-.")
+	if index <= 0 {
+		return instanceType
+	}
+	return instanceType[:index]
+}
+
+// SuggestInstanceTypes returns the known instance types closest to an unknown one, those of its family first, then by
+// edit distance of their names
+func SuggestInstanceTypes(instanceType string, known []InstanceSpecs) []string {
+	family := InstanceFamily(instanceType)
+	type candidate struct {
+		name       string
+		sameFamily bool
+		distance   int
+	}
+	candidates := []candidate{}
+	for _, specs := range known {
+		distance := editDistance(instanceType, specs.Name)
+		sameFamily := InstanceFamily(specs.Name) == family
+		// Names too different are not worth a suggestion
+		if !sameFamily && distance > len(instanceType)/2 {
+			continue
+		}
+		candidates = append(candidates, candidate{name: specs.Name, sameFamily: sameFamily, distance: distance})
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].sameFamily != candidates[j].sameFamily {
+			return candidates[i].sameFamily
+		}
+		if candidates[i].distance != candidates[j].distance {
+			return candidates[i].distance < candidates[j].distance
+		}
+		return candidates[i].name < candidates[j].name
+	})
+	suggestions := []string{}
+	for _, candidate := range candidates {
+		if len(suggestions) == maxSuggestions {
+			break
+		}
+		suggestions = append(suggestions, candidate.name)
+	}
+	return suggestions
+}
+
+// awsSizeRegex is the size of AWS instance types with a known number of vCPUs, such as 2xlarge
+var awsSizeRegex = regexp.MustCompile(`^(\d*)xlarge$|^large$`)
+
+// FamilyAverageInstanceType returns the specs of an unknown instance type from the average memory per vCPU of its
+// family, false if its family has no known instance type. The vCPUs are read from its size when it follows the naming
+// of the provider (n2-standard-4, m5.2xlarge), else they are the ones of the closest instance type of its family.
+func FamilyAverageInstanceType(instanceType string, known []InstanceSpecs) (InstanceSpecs, bool) {
+	family := InstanceFamily(instanceType)
+	members := []InstanceSpecs{}
+	var memoryPerVCPU float64
+	for _, specs := range known {
+		if InstanceFamily(specs.Name) != family || specs.VCPUs == 0 {
+			continue
+		}
+		members = append(members, specs)
+		memoryPerVCPU += float64(specs.MemoryMb) / float64(specs.VCPUs)
+	}
+	if len(members) == 0 {
+		return InstanceSpecs{}, false
+	}
+	memoryPerVCPU /= float64(len(members))
+
+	var vcpus int32
+	if len(instanceType) > len(family) {
+		vcpus = sizeVCPUs(instanceType[len(family)+1:])
+	}
+	if vcpus == 0 {
+		vcpus = closestInstanceType(instanceType, members).VCPUs
+	}
+	return InstanceSpecs{
+		Name:     instanceType,
+		VCPUs:    vcpus,
+		MemoryMb: int32(float64(vcpus) * memoryPerVCPU),
+	}, true
+}
+
+// sizeVCPUs returns the vCPUs of the size of an instance type, 0 if they cannot be read from it
+func sizeVCPUs(size string) int32 {
+	// GCP sizes are the number of vCPUs
+	if vcpus, err := strconv.Atoi(size); err == nil {
+		return int32(vcpus)
+	}
+	// AWS large have 2 vCPUs, xlarge 4 and Nxlarge N times 4
+	matches := awsSizeRegex.FindStringSubmatch(size)
+	switch {
+	case matches == nil:
+		return 0
+	case size == "large":
+		return 2
+	case matches[1] == "":
+		return 4
+	}
+	multiple, _ := strconv.Atoi(matches[1])
+	return int32(multiple * 4)
+}
+
+// closestInstanceType returns the instance type with the closest name, known not being empty
+func closestInstanceType(instanceType string, known []InstanceSpecs) InstanceSpecs {
+	closest := known[0]
+	closestDistance := editDistance(instanceType, closest.Name)
+	for _, specs := range known[1:] {
+		distance := editDistance(instanceType, specs.Name)
+		if distance < closestDistance || (distance == closestDistance && specs.Name < closest.Name) {
+			closest = specs
+			closestDistance = distance
+		}
+	}
+	return closest
+}
+
+// editDistance returns the Levenshtein distance between two strings, the number of single character insertions,
+// deletions or substitutions to change one into the other
+func editDistance(a string, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = previous[j-1] + cost
+			if previous[j]+1 < current[j] {
+				current[j] = previous[j] + 1
+			}
+			if current[j-1]+1 < current[j] {
+				current[j] = current[j-1] + 1
+			}
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
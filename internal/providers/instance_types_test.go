@@ -0,0 +1,61 @@
+package providers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var knownInstanceTypes = []InstanceSpecs{
+	{Name: "n2-standard-2", VCPUs: 2, MemoryMb: 8192},
+	{Name: "n2-standard-8", VCPUs: 8, MemoryMb: 32768},
+	{Name: "n2-highmem-2", VCPUs: 2, MemoryMb: 16384},
+	{Name: "e2-micro", VCPUs: 2, MemoryMb: 1024},
+	{Name: "t3.medium", VCPUs: 2, MemoryMb: 4096},
+	{Name: "t3.xlarge", VCPUs: 4, MemoryMb: 16384},
+}
+
+func TestInstanceFamily(t *testing.T) {
+	assert.Equal(t, "n2-standard", InstanceFamily("n2-standard-4"))
+	assert.Equal(t, "e2", InstanceFamily("e2-micro"))
+	assert.Equal(t, "t3", InstanceFamily("t3.micro"))
+	assert.Equal(t, "db.t3", InstanceFamily("db.t3.micro"))
+	assert.Equal(t, "unknown", InstanceFamily("unknown"))
+}
+
+func TestSuggestInstanceTypes(t *testing.T) {
+	// Family first, then by edit distance, names of other families too different being left out
+	assert.Equal(t, []string{"n2-standard-2", "n2-standard-8"}, SuggestInstanceTypes("n2-standard-4", knownInstanceTypes))
+	assert.Equal(t, []string{"t3.xlarge", "t3.medium"}, SuggestInstanceTypes("t3.large", knownInstanceTypes))
+	assert.Equal(t, []string{}, SuggestInstanceTypes("z9-unknown-16", knownInstanceTypes))
+}
+
+func TestFamilyAverageInstanceType(t *testing.T) {
+	tests := []struct {
+		instanceType string
+		want         InstanceSpecs
+	}{
+		{"n2-standard-4", InstanceSpecs{Name: "n2-standard-4", VCPUs: 4, MemoryMb: 16384}},
+		{"t3.large", InstanceSpecs{Name: "t3.large", VCPUs: 2, MemoryMb: 6144}},
+		{"t3.4xlarge", InstanceSpecs{Name: "t3.4xlarge", VCPUs: 16, MemoryMb: 49152}},
+		// Size without vCPUs, those of the closest instance type of the family
+		{"t3.small", InstanceSpecs{Name: "t3.small", VCPUs: 4, MemoryMb: 12288}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.instanceType, func(t *testing.T) {
+			got, ok := FamilyAverageInstanceType(tt.instanceType, knownInstanceTypes)
+			assert.True(t, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	_, ok := FamilyAverageInstanceType("x1.large", knownInstanceTypes)
+	assert.False(t, ok)
+}
+
+func TestEditDistance(t *testing.T) {
+	assert.Equal(t, 0, editDistance("t3.micro", "t3.micro"))
+	assert.Equal(t, 1, editDistance("t3.micro", "t3.micra"))
+	assert.Equal(t, 3, editDistance("kitten", "sitting"))
+	assert.Equal(t, 5, editDistance("", "large"))
+}
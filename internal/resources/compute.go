@@ -1,6 +1,8 @@
 package resources
 
 import (
+	"encoding/json"
+
 	"github.com/carboniferio/carbonifer/internal/providers"
 	"github.com/shopspring/decimal"
 )
@@ -13,8 +15,55 @@ type ComputeResourceSpecs struct {
 	MemoryMb   int32
 	VCPUs      int32
 	CPUType    string
+	// CPUTypes are the CPU platforms the machine type can run on, used when CPUType is not set
+	CPUTypes []string
+	// FractionalVCPUs is the number of vCPUs when it is not a whole number (serverless), VCPUs is then 0
+	FractionalVCPUs decimal.Decimal
+	// NetworkEgressGb is the declared monthly data transfer of the whole resource, in GB
+	NetworkEgressGb decimal.Decimal
+	// ArchiveStorage is the size of object storage in archive storage classes, in GB
+	ArchiveStorage decimal.Decimal
+	// AvgCPUUse and AvgGPUUse are the utilization of this resource when the source is set, otherwise the provider default is used
+	AvgCPUUse       decimal.Decimal
+	AvgCPUUseSource string
+	AvgGPUUse       decimal.Decimal
+	AvgGPUUseSource string
+	// Provenance is where the value of each property read from the plan comes from, such as vCPUs or storage[0].size
+	Provenance map[string]Provenance `json:",omitempty"`
+}
+
+// Provenance is where the value of a property of a resource comes from
+type Provenance struct {
+	Source    string   // one of the Provenance* sources
+	Path      string   `json:",omitempty"` // jq path of the value in the plan, placeholders resolved
+	Reference string   `json:",omitempty"` // data file, general mapping or referenced resource the value was looked up in
+	Key       string   `json:",omitempty"` // key looked up in the data file, such as the machine type
+	Regex     string   `json:",omitempty"` // regular expression applied to the value
+	Config    []string `json:",omitempty"` // config keys of the placeholders of the path
+	// Known keys similar to a key that was not found, such as machine types of the same family
+	Suggestions []string `json:",omitempty"`
 }
 
+// Sources of the values of properties
+const (
+	ProvenancePlan          = "plan"                // read in the resource in the plan
+	ProvenanceReference     = "referenced resource" // read in another resource of the plan, such as a launch template
+	ProvenanceDataFile      = "data file"           // looked up in a data file or in the general mappings, such as machine types
+	ProvenanceRegex         = "regex"               // extracted by a regular expression, such as custom machine types
+	ProvenanceDefault       = "default"             // default value of the mapping, not read from the plan
+	ProvenanceConfig        = "config"              // computed from config values, such as the average autoscaler size
+	ProvenanceNotFound      = "not found"           // key missing from the data file, such as an unknown machine type, the value being zero
+	ProvenanceFamilyAverage = "family average"      // estimated from the average memory per vCPU of the family of an unknown instance type
+)
+
+// Sources of assumptions that are not the provider defaults
+const (
+	SourceConfig      = "config"
+	SourceTag         = "tag"
+	SourceLabel       = "label"
+	SourceAssumptions = "assumptions file"
+)
+
 // ResourceIdentification is the struct that contains the identification of a resource
 type ResourceIdentification struct {
 	// Indentification
@@ -25,6 +74,14 @@ type ResourceIdentification struct {
 	Count             int64
 	ReplicationFactor int32
 	Address           string
+	// UsageRatio is the average share of time instances are running (serverless), 0 means always running
+	UsageRatio decimal.Decimal
+	// Spot is true when instances are spot or preemptible, as declared in the assumptions file
+	Spot bool `json:",omitempty"`
+	// CountLow and CountHigh are the counts of an autoscaled resource when its average size varies within
+	// the uncertainty range, both 0 if the count does not depend on the autoscaler
+	CountLow  int64 `json:",omitempty"`
+	CountHigh int64 `json:",omitempty"`
 }
 
 // ComputeResource is the struct that contains the info of a compute resource
@@ -68,6 +125,42 @@ func (r UnsupportedResource) GetAddress() string {
 	return r.Identification.Address
 }
 
+// FailedResource is the struct that contains the info of a resource that could not be read, with the reason
+type FailedResource struct {
+	Identification *ResourceIdentification
+	Err            error
+}
+
+// IsSupported returns true if the resource is supported, false otherwise
+func (r FailedResource) IsSupported() bool {
+	return true
+}
+
+// GetIdentification returns the identification of the resource
+func (r FailedResource) GetIdentification() *ResourceIdentification {
+	return r.Identification
+}
+
+// GetAddress returns the address of the resource
+func (r FailedResource) GetAddress() string {
+	return r.Identification.Address
+}
+
+// MarshalJSON writes the error of the resource as its message, errors having no exported fields
+func (r FailedResource) MarshalJSON() ([]byte, error) {
+	reason := ""
+	if r.Err != nil {
+		reason = r.Err.Error()
+	}
+	return json.Marshal(struct {
+		Identification *ResourceIdentification
+		Err            string
+	}{
+		Identification: r.Identification,
+		Err:            reason,
+	})
+}
+
 // Resource is the interface that contains the info of a resource
 type Resource interface {
 	IsSupported() bool
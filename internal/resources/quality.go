@@ -0,0 +1,67 @@
+package resources
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Data quality of the specs of a compute resource
+const (
+	DataQualityHigh   = "high"   // specs read from the plan or looked up in data files
+	DataQualityMedium = "medium" // some specs are default values of the mappings, such as the size of a disk
+	DataQualityLow    = "low"    // some specs were not found or estimated from the family of an unknown machine type
+)
+
+// expectedDefaults are the properties whose default value is the expected one rather than a guess, not worth a warning
+var expectedDefaults = map[string]bool{
+	"replication_factor": true,
+	"count":              true,
+}
+
+// DataQuality returns the data quality of the specs from the provenance of their properties, with a warning for the
+// properties that are default values or were not found, sorted. Properties with the same warning share it.
+func (specs ComputeResourceSpecs) DataQuality() (string, []string) {
+	quality := DataQualityHigh
+	keysByMessage := map[string][]string{}
+	for key, provenance := range specs.Provenance {
+		var message string
+		switch provenance.Source {
+		case ProvenanceNotFound:
+			quality = DataQualityLow
+			message = fmt.Sprintf("'%v' not found in %v%v", provenance.Key, provenance.Reference, formatSuggestions(provenance.Suggestions))
+		case ProvenanceFamilyAverage:
+			quality = DataQualityLow
+			message = fmt.Sprintf("unknown '%v' estimated from the average of the %v%v", provenance.Key, provenance.Reference, formatSuggestions(provenance.Suggestions))
+		case ProvenanceDefault:
+			if expectedDefaults[key] {
+				continue
+			}
+			if quality == DataQualityHigh {
+				quality = DataQualityMedium
+			}
+			message = "default value"
+			if provenance.Reference != "" {
+				message = fmt.Sprintf("%v (%v)", message, provenance.Reference)
+			}
+		default:
+			continue
+		}
+		keysByMessage[message] = append(keysByMessage[message], key)
+	}
+	var warnings []string
+	for message, keys := range keysByMessage {
+		sort.Strings(keys)
+		warnings = append(warnings, fmt.Sprintf("%v: %v", strings.Join(keys, ", "), message))
+	}
+	sort.Strings(warnings)
+	return quality, warnings
+}
+
+// formatSuggestions formats the known keys similar to a key that was not found, if any
+func formatSuggestions(suggestions []string) string {
+	if len(suggestions) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(", did you mean '%v'?", strings.Join(suggestions, "', '"))
+}
@@ -72,7 +72,10 @@ func describeInstanceTypesPaginated(svc *ec2.EC2, instances *map[string]instance
 		gpus := []string{}
 		if gpuInfos != nil {
 			for _, gpu := range gpuInfos.Gpus {
-				gpus = append(gpus, *gpu.Name)
+				// One item per GPU, the instance type having Count GPUs of each model
+				for i := int64(0); i < *gpu.Count; i++ {
+					gpus = append(gpus, *gpu.Name)
+				}
 			}
 			totalGPUMemoryMb = *gpuInfos.TotalGpuMemoryInMiB
 		}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/carboniferio/carbonifer/internal/providers/gcp"
+)
+
+// Capacity tiers of Memorystore for Redis, by provisioned memory
+// https://cloud.google.com/memorystore/docs/redis/pricing
+//
+// GCP does not publish the vCPUs of Memorystore nodes. Redis runs its commands on a single thread,
+// so the smallest tiers are assumed to run on one vCPU, and larger tiers on the vCPUs of a
+// general purpose machine type of the same memory (4 GB per vCPU), capped at 4 vCPUs as Redis does
+// not scale on more cores.
+var capacityTiers = []gcp.RedisTier{
+	{Name: "M1", MinMemoryGb: 1, MaxMemoryGb: 4},
+	{Name: "M2", MinMemoryGb: 5, MaxMemoryGb: 10},
+	{Name: "M3", MinMemoryGb: 11, MaxMemoryGb: 35},
+	{Name: "M4", MinMemoryGb: 36, MaxMemoryGb: 100},
+	{Name: "M5", MinMemoryGb: 101, MaxMemoryGb: 300},
+}
+
+const memoryGbPerVCPU = 4
+const maxVCPUs = 4
+
+func getVCPUs(tier gcp.RedisTier) int64 {
+	vCPUs := tier.MinMemoryGb / memoryGbPerVCPU
+	if vCPUs < 1 {
+		vCPUs = 1
+	}
+	if vCPUs > maxVCPUs {
+		vCPUs = maxVCPUs
+	}
+	return vCPUs
+}
+
+func main() {
+	tiersList := make(map[string]gcp.RedisTier)
+	for _, tier := range capacityTiers {
+		tier.Vcpus = getVCPUs(tier)
+		tiersList[tier.Name] = tier
+	}
+
+	// Generate the JSON representation of the list
+	jsonData, err := json.MarshalIndent(tiersList, "", "  ")
+	if err != nil {
+		log.Fatalf("Error generating JSON: %v", err)
+	}
+
+	fmt.Println(string(jsonData))
+}
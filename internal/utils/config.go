@@ -34,7 +34,21 @@ func InitWithConfig(customConfigFilePath string) {
 //go:embed defaults.yaml
 var defaultConfigFile []byte
 
+// NewDefaultConfig returns a new configuration with the default values, independent of the global one
+func NewDefaultConfig() *viper.Viper {
+	v := viper.New()
+	setDefaults(v)
+	return v
+}
+
 func loadViperDefaults() {
+	setDefaults(viper.GetViper())
+	settings := viper.AllSettings()
+
+	log.Debug(settings)
+}
+
+func setDefaults(v *viper.Viper) {
 	var defaults map[string]interface{}
 
 	err := yaml.Unmarshal(defaultConfigFile, &defaults)
@@ -42,12 +56,9 @@ func loadViperDefaults() {
 		log.Fatal(err)
 	}
 
-	for k, v := range defaults {
-		viper.SetDefault(k, v)
+	for key, value := range defaults {
+		v.SetDefault(key, value)
 	}
-	settings := viper.AllSettings()
-
-	log.Debug(settings)
 }
 
 func basePath() string {
@@ -3,6 +3,8 @@ package utils
 import (
 	"fmt"
 	"strconv"
+
+	"github.com/shopspring/decimal"
 )
 
 // ParseToInt converts to an int an interface that could be int, float or string
@@ -29,6 +31,20 @@ func ParseToInt(value interface{}) (int, error) {
 	}
 }
 
+// ParseToDecimal converts to a decimal an interface that could be int, float or string
+func ParseToDecimal(value interface{}) (decimal.Decimal, error) {
+	switch v := value.(type) {
+	case int:
+		return decimal.NewFromInt(int64(v)), nil
+	case float64:
+		return decimal.NewFromFloat(v), nil
+	case string:
+		return decimal.NewFromString(v)
+	default:
+		return decimal.Zero, fmt.Errorf("Cannot convert interface to decimal: %v", value)
+	}
+}
+
 // ConvertInterfaceListToStringList converts a list of interfaces to a list of strings
 func ConvertInterfaceListToStringList(list []interface{}) []string {
 	stringList := []string{}
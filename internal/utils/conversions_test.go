@@ -36,6 +36,36 @@ func TestParseToInt(t *testing.T) {
 	}
 }
 
+func TestParseToDecimal(t *testing.T) {
+	type args struct {
+		value interface{}
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    string
+		wantErr bool
+	}{
+		{"int", args{1}, "1", false},
+		{"float", args{0.25}, "0.25", false},
+		{"string", args{"2"}, "2", false},
+		{"stringFloat", args{"0.5"}, "0.5", false},
+		{"stringErr", args{"a"}, "0", true},
+		{"typeErr", args{true}, "0", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseToDecimal(tt.args.value)
+			assert.Equal(t, tt.want, got.String())
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
 func TestConvertInterfaceListToStringList(t *testing.T) {
 	type args struct {
 		list []interface{}
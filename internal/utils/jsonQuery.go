@@ -18,6 +18,28 @@ func (*moduleLoader) LoadModule(name string) (*gojq.Query, error) {
 
 			def all_select(a; b):
 				.planned_values | .. | objects | select(has("resources")) | .resources[] | select(.[a] == b);
+
+			# Address without indexes of count and for_each, as in the configuration
+			def config_address:
+				gsub("\\[[^\\]]*\\]"; "");
+
+			def _config_resources($module_address):
+				(.resources[]? | . + {module_address: $module_address}),
+				((.module_calls // {}) | to_entries[] | .key as $name | .value.module | _config_resources($module_address + "module." + $name + "."));
+
+			# Resources of the configuration of all modules, with the address of their module as prefix of their references
+			def config_resources:
+				.configuration.root_module | _config_resources("");
+
+			# True if the attribute of this configuration resource references the resource of the address
+			def references($attribute; $address):
+				.module_address as $module_address | ($address | config_address) as $target |
+				any(.expressions[$attribute]?.references[]?; ($module_address + .) | config_address | . == $target or startswith($target + "."));
+
+			# Planned resources of a configuration resource, one per index of count and for_each
+			def planned_resources($config_resource):
+				.planned_values.root_module | recurse(.child_modules[]?) | .resources[]? |
+				select(.address | config_address | . == $config_resource.module_address + $config_resource.address);
 		`)
 	}
 	return nil, fmt.Errorf("module not found: %q", name)
@@ -62,6 +84,16 @@ func GetJSON(query string, json interface{}) ([]interface{}, error) {
 	return results, nil
 }
 
+// CompileJSONQuery returns an error if a jq query, that can use the carbonifer module as cbf::, does not compile
+func CompileJSONQuery(query string) error {
+	queryParsed, err := gojq.Parse(fmt.Sprintf(`import "carbonifer" as cbf; %s`, query))
+	if err != nil {
+		return err
+	}
+	_, err = gojq.Compile(queryParsed, *getGoJQWithModules())
+	return err
+}
+
 var goJqWithModules *gojq.CompilerOption
 
 func getGoJQWithModules() *gojq.CompilerOption {
@@ -98,3 +98,56 @@ func jsonParse(jsonString string) map[string]interface{} {
 	}
 	return result
 }
+
+func TestCompileJSONQuery(t *testing.T) {
+	assert.NoError(t, CompileJSONQuery(".values.machine_type"))
+	assert.NoError(t, CompileJSONQuery(`cbf::all_select("type"; "aws_ami") | .values`))
+	assert.Error(t, CompileJSONQuery(".values | select(.name\","))
+	assert.Error(t, CompileJSONQuery("cbf::unknown_function(1)"))
+	assert.Error(t, CompileJSONQuery("unknown_function(1)"))
+}
+
+func TestGetJSON_ConfigResources(t *testing.T) {
+	tfPlan := jsonParse(`
+	{
+		"planned_values": {
+			"root_module": {
+				"child_modules": [
+					{
+						"address": "module.storage[\"logs\"]",
+						"resources": [
+							{"address": "module.storage[\"logs\"].aws_s3_bucket.this[0]", "type": "aws_s3_bucket"},
+							{"address": "module.storage[\"logs\"].aws_s3_bucket_replication_configuration.this[0]", "type": "aws_s3_bucket_replication_configuration"}
+						]
+					}
+				]
+			}
+		},
+		"configuration": {
+			"root_module": {
+				"module_calls": {
+					"storage": {
+						"module": {
+							"resources": [
+								{"address": "aws_s3_bucket.this", "type": "aws_s3_bucket"},
+								{
+									"address": "aws_s3_bucket_replication_configuration.this",
+									"type": "aws_s3_bucket_replication_configuration",
+									"expressions": {"bucket": {"references": ["aws_s3_bucket.this[0].id", "aws_s3_bucket.this[0]", "aws_s3_bucket.this"]}}
+								}
+							]
+						}
+					}
+				}
+			}
+		}
+	}`)
+
+	result, err := GetJSON(`[cbf::config_resources | select(cbf::references("bucket"; "module.storage[\"logs\"].aws_s3_bucket.this[0]")) | .module_address + .address]`, tfPlan)
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{[]interface{}{"module.storage.aws_s3_bucket_replication_configuration.this"}}, result)
+
+	result, err = GetJSON(`. as $plan | cbf::config_resources | select(.type == "aws_s3_bucket_replication_configuration") as $config_resource | $plan | cbf::planned_resources($config_resource) | .address`, tfPlan)
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{"module.storage[\"logs\"].aws_s3_bucket_replication_configuration.this[0]"}, result)
+}
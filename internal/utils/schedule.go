@@ -0,0 +1,137 @@
+package utils
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+)
+
+const minutesPerWeek = 7 * 24 * 60
+
+var cronDayNames = strings.NewReplacer("SUN", "0", "MON", "1", "TUE", "2", "WED", "3", "THU", "4", "FRI", "5", "SAT", "6")
+
+// WeeklyRunningRatio returns the share of a week a resource is running, given the cron expressions of its start and stop events.
+// Cron expressions are "minute hour day-of-month month day-of-week", day of month and month are ignored.
+func WeeklyRunningRatio(startCrons []string, stopCrons []string) (decimal.Decimal, error) {
+	if len(startCrons) == 0 || len(stopCrons) == 0 {
+		return decimal.Zero, errors.Errorf("A schedule needs both start and stop events: %v starts, %v stops", len(startCrons), len(stopCrons))
+	}
+
+	// Events of the week, by minute: 1 start, -1 stop
+	events := make([]int, minutesPerWeek)
+	for _, cron := range startCrons {
+		minutes, err := cronWeekMinutes(cron)
+		if err != nil {
+			return decimal.Zero, err
+		}
+		for _, minute := range minutes {
+			if events[minute] == 0 {
+				events[minute] = 1
+			}
+		}
+	}
+	for _, cron := range stopCrons {
+		minutes, err := cronWeekMinutes(cron)
+		if err != nil {
+			return decimal.Zero, err
+		}
+		for _, minute := range minutes {
+			events[minute] = -1
+		}
+	}
+
+	// State at the beginning of the week is the one of the last event of the previous week
+	running := false
+	for minute := minutesPerWeek - 1; minute >= 0; minute-- {
+		if events[minute] != 0 {
+			running = events[minute] == 1
+			break
+		}
+	}
+
+	runningMinutes := 0
+	for _, event := range events {
+		if event != 0 {
+			running = event == 1
+		}
+		if running {
+			runningMinutes++
+		}
+	}
+	return decimal.NewFromInt(int64(runningMinutes)).Div(decimal.NewFromInt(minutesPerWeek)), nil
+}
+
+// cronWeekMinutes returns the minutes of the week (from Sunday 00:00) matched by a cron expression
+func cronWeekMinutes(cron string) ([]int, error) {
+	fields := strings.Fields(cron)
+	if len(fields) < 5 {
+		return nil, errors.Errorf("Invalid cron expression '%v': expected 5 fields", cron)
+	}
+	minutes, err := parseCronField(fields[0], 0, 59)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Invalid minutes in cron expression '%v'", cron)
+	}
+	hours, err := parseCronField(fields[1], 0, 23)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Invalid hours in cron expression '%v'", cron)
+	}
+	days, err := parseCronField(cronDayNames.Replace(strings.ToUpper(fields[4])), 0, 7)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Invalid days of week in cron expression '%v'", cron)
+	}
+
+	weekMinutes := []int{}
+	for _, day := range days {
+		// 7 is also Sunday
+		day = day % 7
+		for _, hour := range hours {
+			for _, minute := range minutes {
+				weekMinutes = append(weekMinutes, day*24*60+hour*60+minute)
+			}
+		}
+	}
+	return weekMinutes, nil
+}
+
+// parseCronField returns the values of a cron field: `*`, `n`, `a-b`, with optional `/step`, separated by commas
+func parseCronField(field string, min int, max int) ([]int, error) {
+	values := []int{}
+	for _, part := range strings.Split(field, ",") {
+		step := 1
+		if rangePart, stepPart, found := strings.Cut(part, "/"); found {
+			var err error
+			step, err = strconv.Atoi(stepPart)
+			if err != nil || step <= 0 {
+				return nil, errors.Errorf("invalid step '%v'", stepPart)
+			}
+			part = rangePart
+		}
+		start, end := min, max
+		if part != "*" && part != "?" {
+			startPart, endPart, isRange := strings.Cut(part, "-")
+			var err error
+			start, err = strconv.Atoi(startPart)
+			if err != nil {
+				return nil, errors.Errorf("invalid value '%v'", startPart)
+			}
+			end = start
+			if isRange {
+				end, err = strconv.Atoi(endPart)
+				if err != nil {
+					return nil, errors.Errorf("invalid value '%v'", endPart)
+				}
+			} else if step > 1 {
+				end = max
+			}
+		}
+		if start < min || end > max || start > end {
+			return nil, errors.Errorf("'%v' is out of range %v-%v", part, min, max)
+		}
+		for value := start; value <= end; value += step {
+			values = append(values, value)
+		}
+	}
+	return values, nil
+}
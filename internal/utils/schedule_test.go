@@ -0,0 +1,65 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWeeklyRunningRatio(t *testing.T) {
+	tests := []struct {
+		name    string
+		starts  []string
+		stops   []string
+		want    decimal.Decimal
+		wantErr bool
+	}{
+		{
+			name:   "office hours",
+			starts: []string{"0 8 * * 1-5"},
+			stops:  []string{"0 20 * * 1-5"},
+			want:   decimal.NewFromInt(5 * 12).Div(decimal.NewFromInt(7 * 24)),
+		},
+		{
+			name:   "stopped on weekends",
+			starts: []string{"0 0 * * MON"},
+			stops:  []string{"0 0 * * SAT"},
+			want:   decimal.NewFromInt(5).Div(decimal.NewFromInt(7)),
+		},
+		{
+			name:   "stopped at night, Sunday as 7",
+			starts: []string{"30 6 * * *"},
+			stops:  []string{"30 22 * * 0-7"},
+			want:   decimal.NewFromInt(16).Div(decimal.NewFromInt(24)),
+		},
+		{
+			name:   "several starts",
+			starts: []string{"0 8 * * 1", "0 8 * * 3"},
+			stops:  []string{"0 12 * * 1,3"},
+			want:   decimal.NewFromInt(8).Div(decimal.NewFromInt(7 * 24)),
+		},
+		{
+			name:    "no stop",
+			starts:  []string{"0 8 * * 1-5"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid cron",
+			starts:  []string{"0 25 * * 1-5"},
+			stops:   []string{"0 20 * * 1-5"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := WeeklyRunningRatio(tt.starts, tt.stops)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want.Round(10).String(), got.Round(10).String())
+		})
+	}
+}
@@ -1,9 +1,10 @@
 package estimate
 
 import (
-	"github.com/carboniferio/carbonifer/internal/estimate"
+	"io"
+	"sync"
+
 	internalResources "github.com/carboniferio/carbonifer/internal/resources"
-	"github.com/carboniferio/carbonifer/internal/utils"
 	"github.com/carboniferio/carbonifer/pkg/providers"
 	"github.com/carboniferio/carbonifer/pkg/resources"
 	"github.com/shopspring/decimal"
@@ -16,47 +17,65 @@ type EstimationReport struct {
 	CarbonEmissions decimal.Decimal `json:"CarbonEmissionsPerInstance"`
 	AverageCPUUsage decimal.Decimal
 	Count           decimal.Decimal
+	// CPU platform assumed for the CPU power, empty if the flat coefficients of the provider are used
+	CPUPlatform string `json:",omitempty"`
+}
+
+var defaultEstimator *Estimator
+var defaultEstimatorErr error
+var defaultEstimatorOnce sync.Once
+
+// DefaultEstimator returns the Estimator with the default options
+func DefaultEstimator() (*Estimator, error) {
+	defaultEstimatorOnce.Do(func() {
+		defaultEstimator, defaultEstimatorErr = NewEstimator(Options{})
+	})
+	return defaultEstimator, defaultEstimatorErr
 }
 
-// GetEstimation returns the estimation of a resource
+// GetEstimation returns the estimation of a resource, with the default options
 func GetEstimation(resource resources.GenericResource) (EstimationReport, error) {
-	estimation, err := estimate.EstimateResource(toInternalComputeResource(resource))
+	estimator, err := DefaultEstimator()
 	if err != nil {
 		return EstimationReport{}, err
 	}
-	// Exponent is enforced to avoid equality issues when comparing reports.
-	// Indeed, if we don't truncate the values, we might have value with various exponent,
-	// which will make the equality check fail during test.
-	// TODO: Find a better way to handle this
-	return EstimationReport{
-		Resource:        resource,
-		Power:           estimation.Power.Truncate(10),
-		CarbonEmissions: estimation.CarbonEmissions.Truncate(10),
-		AverageCPUUsage: estimation.AverageCPUUsage.Truncate(10),
-		Count:           estimation.TotalCount.Truncate(10),
-	}, nil
+	return estimator.GetEstimation(resource)
 }
 
-// GetEstimationFromInstanceType returns the estimation of a resource from its instance type
+// GetEstimationFromInstanceType returns the estimation of a resource from its instance type, with the default options
 func GetEstimationFromInstanceType(instanceType string, zone string, provider providers.Provider) (EstimationReport, error) {
-	resource, err := resources.GetResource(instanceType, zone, provider)
+	estimator, err := DefaultEstimator()
 	if err != nil {
 		return EstimationReport{}, err
 	}
-	estimation, err := GetEstimation(resource)
+	return estimator.GetEstimationFromInstanceType(instanceType, zone, provider)
+}
+
+// EstimatePlan returns the estimation report of a terraform plan in JSON, with the default options
+func EstimatePlan(planJSON []byte) (PlanReport, error) {
+	estimator, err := DefaultEstimator()
 	if err != nil {
-		return EstimationReport{}, err
+		return PlanReport{}, err
 	}
-	return estimation, nil
+	return estimator.EstimatePlan(planJSON)
+}
+
+// EstimatePlanFromReader returns the estimation report of a terraform plan in JSON read from r, with the default options
+func EstimatePlanFromReader(r io.Reader) (PlanReport, error) {
+	estimator, err := DefaultEstimator()
+	if err != nil {
+		return PlanReport{}, err
+	}
+	return estimator.EstimatePlanFromReader(r)
 }
 
 func toInternalComputeResource(resource resources.GenericResource) internalResources.ComputeResource {
-	// TODO: Support multiple CPU types
-	// Check this PR for more info: https://github.com/carboniferio/carbonifer/pull/41
+	// The CPU platforms are estimated according to the CPU platform policy
 	return internalResources.ComputeResource{
 		Identification: resource.GetIdentification(),
 		Specs: &internalResources.ComputeResourceSpecs{
 			GpuTypes:   resource.GPUTypes,
+			CPUTypes:   resource.CPUTypes,
 			HddStorage: resource.Storage.HddStorage,
 			SsdStorage: resource.Storage.SsdStorage,
 			MemoryMb:   resource.MemoryMb,
@@ -64,7 +83,3 @@ func toInternalComputeResource(resource resources.GenericResource) internalResou
 		},
 	}
 }
-
-func init() {
-	utils.InitWithDefaultConfig()
-}
@@ -77,3 +77,56 @@ func TestGetEstimation(t *testing.T) {
 		})
 	}
 }
+
+func TestGetEstimationFromInstanceType(t *testing.T) {
+	type args struct {
+		instanceType string
+		zone         string
+		provider     providers.Provider
+	}
+	tests := []struct {
+		name       string
+		args       args
+		wantRegion string
+		wantErr    bool
+	}{
+		{
+			name:       "GCP e2-standard-2",
+			args:       args{"e2-standard-2", "europe-west4-a", providers.GCP},
+			wantRegion: "europe-west4",
+		},
+		{
+			name:       "AWS m6i.large",
+			args:       args{"m6i.large", "eu-west-1a", providers.AWS},
+			wantRegion: "eu-west-1",
+		},
+		{
+			name:       "AWS p3.8xlarge",
+			args:       args{"p3.8xlarge", "us-east-1", providers.AWS},
+			wantRegion: "us-east-1",
+		},
+		{
+			name:    "Azure",
+			args:    args{"Standard_D2s_v3", "westeurope", providers.AZURE},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetEstimationFromInstanceType(tt.args.instanceType, tt.args.zone, tt.args.provider)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetEstimationFromInstanceType() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if got.Resource.Region != tt.wantRegion {
+				t.Errorf("GetEstimationFromInstanceType() region = %v, want %v", got.Resource.Region, tt.wantRegion)
+			}
+			if !got.CarbonEmissions.IsPositive() {
+				t.Errorf("GetEstimationFromInstanceType() emissions = %v, want positive", got.CarbonEmissions)
+			}
+		})
+	}
+}
@@ -0,0 +1,192 @@
+package estimate
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/carboniferio/carbonifer/internal/estimate"
+	"github.com/carboniferio/carbonifer/internal/utils"
+	"github.com/carboniferio/carbonifer/pkg/providers"
+	"github.com/carboniferio/carbonifer/pkg/resources"
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+)
+
+// Options are the settings of an Estimator. Empty fields are taken from Config, or else from the defaults.
+type Options struct {
+	// Units of the estimations
+	Units Units
+	// Average utilization of the resources, per provider
+	Utilization map[providers.Provider]Utilization
+	// How machine types running on several CPU platforms are estimated: "flat" coefficients of the provider,
+	// "average" of the platforms, or the platform with the "min" or "max" power
+	CPUPlatformPolicy string
+	// Directory of the data files (coefficients, instance types, regions...). Files missing there are taken from the embedded ones
+	DataPath string
+	// Directory of the mappings of terraform resources
+	MappingsPath string
+	// Other settings, structured as in the config file, such as {"uncertainty": {"pue": 0.1}}
+	Config map[string]interface{}
+}
+
+// Units are the units of the estimations
+type Units struct {
+	Time   string // "h", "m" or "y"
+	Power  string // "W" or "kW"
+	Carbon string // "g" or "kg"
+}
+
+// Utilization is the average utilization of the resources of a provider. Nil fields are taken from Config, or else from the defaults.
+type Utilization struct {
+	AvgCPUUse                *float64 // between 0 and 1
+	AvgGPUUse                *float64 // between 0 and 1
+	AvgAutoscalerSizePercent *float64 // between 0 (min size) and 1 (max size)
+}
+
+// Estimator estimates the emissions of resources with its own options, independently of the global configuration.
+//
+// It is safe for concurrent use: estimations of the same or different estimators run in parallel.
+type Estimator struct {
+	config *viper.Viper
+}
+
+var allowedValues = map[string][]string{
+	"unit.time":                        {"h", "m", "y"},
+	"unit.power":                       {"W", "kW"},
+	"unit.carbon":                      {"g", "kg"},
+	"provider.gcp.cpu_platform_policy": {"flat", "average", "min", "max"},
+}
+
+// NewEstimator returns an Estimator with the given options
+func NewEstimator(options Options) (*Estimator, error) {
+	v := utils.NewDefaultConfig()
+	if options.Config != nil {
+		if err := v.MergeConfigMap(options.Config); err != nil {
+			return nil, errors.Wrap(err, "Invalid config")
+		}
+	}
+
+	units := map[string]string{
+		"unit.time":   options.Units.Time,
+		"unit.power":  options.Units.Power,
+		"unit.carbon": options.Units.Carbon,
+	}
+	for key, unit := range units {
+		if unit != "" {
+			v.Set(key, unit)
+		}
+	}
+	if options.CPUPlatformPolicy != "" {
+		v.Set("provider.gcp.cpu_platform_policy", options.CPUPlatformPolicy)
+	}
+	for key, allowed := range allowedValues {
+		if !contains(allowed, v.GetString(key)) {
+			return nil, errors.Errorf("Invalid %v '%v', must be one of %v", key, v.GetString(key), strings.Join(allowed, ", "))
+		}
+	}
+
+	for provider, utilization := range options.Utilization {
+		prefix := fmt.Sprintf("provider.%v", strings.ToLower(provider.String()))
+		values := map[string]*float64{
+			prefix + ".avg_cpu_use":                 utilization.AvgCPUUse,
+			prefix + ".avg_gpu_use":                 utilization.AvgGPUUse,
+			prefix + ".avg_autoscaler_size_percent": utilization.AvgAutoscalerSizePercent,
+		}
+		for key, value := range values {
+			if value != nil {
+				v.Set(key, *value)
+			}
+		}
+	}
+
+	if options.DataPath != "" {
+		v.Set("data.path", options.DataPath)
+	}
+	dataPath, err := checkDirectory(v.GetString("data.path"), "data")
+	if err != nil {
+		return nil, err
+	}
+	v.Set("data.path", dataPath)
+
+	if options.MappingsPath != "" {
+		v.Set("mappings.path", options.MappingsPath)
+	}
+	mappingsPath, err := checkDirectory(v.GetString("mappings.path"), "mappings")
+	if err != nil {
+		return nil, err
+	}
+	v.Set("mappings.path", mappingsPath)
+
+	return &Estimator{config: v}, nil
+}
+
+// Config returns the configuration of carbonifer set by the options of the estimator, to be passed to the estimations
+// made with carbonifer internal packages, such as by the CLI. It must not be modified.
+func (e *Estimator) Config() *viper.Viper {
+	return e.config
+}
+
+// GetEstimation returns the estimation of a resource
+func (e *Estimator) GetEstimation(resource resources.GenericResource) (EstimationReport, error) {
+	estimation, err := estimate.EstimateResource(e.config, toInternalComputeResource(resource))
+	if err != nil {
+		return EstimationReport{}, err
+	}
+	// Exponent is enforced to avoid equality issues when comparing reports.
+	// Indeed, if we don't truncate the values, we might have value with various exponent,
+	// which will make the equality check fail during test.
+	// TODO: Find a better way to handle this
+	return EstimationReport{
+		Resource:        resource,
+		Power:           estimation.Power.Truncate(10),
+		CarbonEmissions: estimation.CarbonEmissions.Truncate(10),
+		AverageCPUUsage: estimation.AverageCPUUsage.Truncate(10),
+		Count:           estimation.TotalCount.Truncate(10),
+		CPUPlatform:     estimation.CPUPlatform,
+	}, nil
+}
+
+// GetEstimationFromInstanceType returns the estimation of a resource from its instance type
+func (e *Estimator) GetEstimationFromInstanceType(instanceType string, zone string, provider providers.Provider) (EstimationReport, error) {
+	resource, err := resources.GetResourceWithDataPath(e.config.GetString("data.path"), instanceType, zone, provider)
+	if err != nil {
+		return EstimationReport{}, err
+	}
+	return e.GetEstimation(resource)
+}
+
+// checkDirectory returns the absolute path of a non empty directory, or an empty path if none is set
+func checkDirectory(directory string, name string) (string, error) {
+	if directory == "" {
+		return "", nil
+	}
+	path, err := filepath.Abs(directory)
+	if err != nil {
+		return "", errors.Wrapf(err, "Invalid %v directory \"%v\"", name, directory)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return "", errors.Wrapf(err, "Cannot read %v directory \"%v\"", name, path)
+	}
+	defer f.Close()
+	_, err = f.Readdirnames(1)
+	if err == io.EOF {
+		return "", errors.Errorf("Empty %v directory \"%v\"", name, path)
+	}
+	if err != nil {
+		return "", errors.Wrapf(err, "Cannot read %v directory \"%v\"", name, path)
+	}
+	return path, nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,152 @@
+package estimate
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	internalProviders "github.com/carboniferio/carbonifer/internal/providers"
+	"github.com/carboniferio/carbonifer/internal/utils"
+	"github.com/carboniferio/carbonifer/pkg/providers"
+	"github.com/carboniferio/carbonifer/pkg/resources"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+var resourceE2Standard2 = resources.GenericResource{
+	Address:  "google_compute_instance.e2-standard-2",
+	Name:     "e2-standard-2",
+	Region:   "europe-west4",
+	Provider: providers.GCP,
+	VCPUs:    2,
+	MemoryMb: 8192,
+}
+
+func TestNewEstimator_InvalidOptions(t *testing.T) {
+	_, err := NewEstimator(Options{Units: Units{Power: "MW"}})
+	assert.ErrorContains(t, err, "Invalid unit.power 'MW'")
+
+	_, err = NewEstimator(Options{Config: map[string]interface{}{"unit": map[string]interface{}{"time": "s"}}})
+	assert.ErrorContains(t, err, "Invalid unit.time 's'")
+
+	_, err = NewEstimator(Options{DataPath: "does/not/exist"})
+	assert.ErrorContains(t, err, "Cannot read data directory")
+}
+
+func TestEstimator_Options(t *testing.T) {
+	defaultEstimator, err := NewEstimator(Options{})
+	assert.NoError(t, err)
+	kiloEstimator, err := NewEstimator(Options{
+		Units: Units{Power: "kW", Carbon: "kg"},
+	})
+	assert.NoError(t, err)
+	busyEstimator, err := NewEstimator(Options{
+		Utilization: map[providers.Provider]Utilization{
+			providers.GCP: {AvgCPUUse: float64Ptr(0.9)},
+		},
+	})
+	assert.NoError(t, err)
+
+	want, err := defaultEstimator.GetEstimation(resourceE2Standard2)
+	assert.NoError(t, err)
+	assert.Equal(t, "8.9166", want.Power.String())
+	assert.Equal(t, "2.5233978", want.CarbonEmissions.String())
+
+	got, err := kiloEstimator.GetEstimation(resourceE2Standard2)
+	assert.NoError(t, err)
+	assert.Equal(t, want.Power.Div(decimal.NewFromInt(1000)).String(), got.Power.String())
+	assert.Equal(t, want.CarbonEmissions.Div(decimal.NewFromInt(1000)).String(), got.CarbonEmissions.String())
+
+	got, err = busyEstimator.GetEstimation(resourceE2Standard2)
+	assert.NoError(t, err)
+	assert.Equal(t, "0.9", got.AverageCPUUsage.String())
+	assert.True(t, got.Power.GreaterThan(want.Power))
+}
+
+func TestNewEstimator_PartialUtilization(t *testing.T) {
+	estimator, err := NewEstimator(Options{
+		Utilization: map[providers.Provider]Utilization{
+			providers.GCP: {AvgGPUUse: float64Ptr(0.2)},
+		},
+	})
+	assert.NoError(t, err)
+	defaults := utils.NewDefaultConfig()
+	assert.Equal(t, 0.2, estimator.config.GetFloat64("provider.gcp.avg_gpu_use"))
+	assert.Equal(t, defaults.GetFloat64("provider.gcp.avg_cpu_use"), estimator.config.GetFloat64("provider.gcp.avg_cpu_use"))
+	assert.Equal(t, defaults.GetFloat64("provider.gcp.avg_autoscaler_size_percent"), estimator.config.GetFloat64("provider.gcp.avg_autoscaler_size_percent"))
+
+	got, err := estimator.GetEstimation(resourceE2Standard2)
+	assert.NoError(t, err)
+	assert.Equal(t, "8.9166", got.Power.String())
+}
+
+func float64Ptr(value float64) *float64 {
+	return &value
+}
+
+func TestEstimator_CPUPlatformPolicy(t *testing.T) {
+	resource := resourceE2Standard2
+	resource.CPUTypes = []string{"Skylake", "Broadwell", "Haswell", "AMD EPYC Rome", "AMD EPYC Milan"}
+
+	flatEstimator, err := NewEstimator(Options{})
+	assert.NoError(t, err)
+	flat, err := flatEstimator.GetEstimation(resource)
+	assert.NoError(t, err)
+	assert.Equal(t, "", flat.CPUPlatform)
+	assert.Equal(t, "8.9166", flat.Power.String())
+
+	minEstimator, err := NewEstimator(Options{CPUPlatformPolicy: "min"})
+	assert.NoError(t, err)
+	got, err := minEstimator.GetEstimation(resource)
+	assert.NoError(t, err)
+	assert.Equal(t, "EPYC 2nd Gen", got.CPUPlatform)
+	assert.True(t, got.Power.LessThan(flat.Power))
+
+	_, err = NewEstimator(Options{CPUPlatformPolicy: "median"})
+	assert.ErrorContains(t, err, "Invalid provider.gcp.cpu_platform_policy 'median'")
+}
+
+func TestEstimator_Concurrent(t *testing.T) {
+	estimators := map[string]*Estimator{}
+	for _, unit := range []string{"W", "kW"} {
+		estimator, err := NewEstimator(Options{Units: Units{Power: unit}})
+		assert.NoError(t, err)
+		estimators[unit] = estimator
+	}
+	want := map[string]string{"W": "8.9166", "kW": "0.0089166"}
+
+	// A machine type only known by the data files of one estimator
+	dataPath := t.TempDir()
+	err := os.WriteFile(filepath.Join(dataPath, "gcp_instances.json"), []byte(`{
+		"e9-test-2": {"name": "e9-test-2", "vcpus": 2, "memoryMb": 8192, "cpuTypes": ["Skylake"]}
+	}`), 0o600)
+	assert.NoError(t, err)
+	dataEstimator, err := NewEstimator(Options{DataPath: dataPath})
+	assert.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		for unit, estimator := range estimators {
+			wg.Add(1)
+			go func(unit string, estimator *Estimator) {
+				defer wg.Done()
+				got, err := estimator.GetEstimation(resourceE2Standard2)
+				assert.NoError(t, err)
+				assert.Equal(t, want[unit], got.Power.String())
+
+				_, err = estimator.GetEstimationFromInstanceType("e9-test-2", "europe-west4-a", providers.GCP)
+				var unknownInstanceTypeError *internalProviders.UnknownInstanceTypeError
+				assert.ErrorAs(t, err, &unknownInstanceTypeError)
+			}(unit, estimator)
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			got, err := dataEstimator.GetEstimationFromInstanceType("e9-test-2", "europe-west4-a", providers.GCP)
+			assert.NoError(t, err)
+			assert.Equal(t, int32(2), got.Resource.VCPUs)
+		}()
+	}
+	wg.Wait()
+}
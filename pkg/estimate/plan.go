@@ -0,0 +1,202 @@
+package estimate
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+
+	internalEstimate "github.com/carboniferio/carbonifer/internal/estimate"
+	"github.com/carboniferio/carbonifer/internal/estimate/estimation"
+	"github.com/carboniferio/carbonifer/internal/plan"
+	internalResources "github.com/carboniferio/carbonifer/internal/resources"
+	"github.com/carboniferio/carbonifer/pkg/providers"
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+	log "github.com/sirupsen/logrus"
+)
+
+// PlanReport is the estimation report of a terraform plan
+type PlanReport struct {
+	Info                 PlanInfo
+	Resources            []ResourceEstimation
+	UnsupportedResources []ResourceIdentification
+	Total                PlanTotal
+}
+
+// PlanInfo is the struct that contains the units and date of a PlanReport
+type PlanInfo struct {
+	UnitTime                string
+	UnitWattTime            string
+	UnitCarbonEmissionsTime string
+	UnitWaterTime           string
+	DateTime                time.Time
+}
+
+// ResourceIdentification is the struct that identifies a resource of a terraform plan
+type ResourceIdentification struct {
+	Address           string
+	Name              string
+	Type              string
+	Provider          providers.Provider
+	Region            string
+	Count             int64
+	ReplicationFactor int32
+	// Spot is true when instances are spot or preemptible, as declared in the assumptions file
+	Spot bool `json:",omitempty"`
+}
+
+// ResourceEstimation is the struct that contains the estimation of a resource of a terraform plan
+type ResourceEstimation struct {
+	Resource        ResourceIdentification
+	Power           decimal.Decimal `json:"PowerPerInstance"`
+	CarbonEmissions decimal.Decimal `json:"CarbonEmissionsPerInstance"`
+	AverageCPUUsage decimal.Decimal
+	AverageGPUUsage decimal.Decimal
+	TotalCount      decimal.Decimal // Count * ReplicationFactor
+	// CPU platform assumed for the CPU power, empty if the flat coefficients of the provider are used
+	CPUPlatform string `json:",omitempty"`
+	// Networking of the whole resource, not per instance
+	NetworkPower           decimal.Decimal
+	NetworkCarbonEmissions decimal.Decimal
+	// Market-based emissions, electricity covered by carbon free energy excluded, null if the carbon free energy is unknown
+	MarketBasedEmissions decimal.NullDecimal `json:"MarketBasedEmissionsPerInstance"`
+	// Embodied emissions of the share of the host used by an instance
+	EmbodiedEmissions decimal.Decimal `json:"EmbodiedEmissionsPerInstance"`
+	// Water used on site by an instance, in Litres, null if the WUE of the region is unknown
+	Water decimal.NullDecimal `json:"WaterPerInstance"`
+	// Low and high estimations, when assumptions vary within their uncertainty
+	PowerRange           Range `json:"PowerPerInstanceRange"`
+	CarbonEmissionsRange Range `json:"CarbonEmissionsPerInstanceRange"`
+	// Data quality of the specs: high, medium if some are defaults of the mappings, low if some were not found
+	DataQuality string `json:",omitempty"`
+	// Specs that are default values or were not found, such as the size of a disk or an unknown machine type
+	Warnings []string `json:",omitempty"`
+	// Reason the resource could not be estimated, its estimations being zero
+	Error string `json:",omitempty"`
+}
+
+// Range is the struct that contains the low and high bounds of an estimation
+type Range struct {
+	Low  decimal.Decimal
+	High decimal.Decimal
+}
+
+// PlanTotal is the struct that contains the total estimation of a terraform plan
+type PlanTotal struct {
+	Power           decimal.Decimal
+	CarbonEmissions decimal.Decimal
+	ResourcesCount  decimal.Decimal
+	// Networking part of Power and CarbonEmissions
+	NetworkPower           decimal.Decimal
+	NetworkCarbonEmissions decimal.Decimal
+	// Market-based emissions, networking included, null if the carbon free energy of a resource is unknown
+	MarketBasedEmissions decimal.NullDecimal
+	// Embodied emissions, not part of CarbonEmissions
+	EmbodiedEmissions decimal.Decimal
+	// Water used on site, in Litres, null if the WUE of the region of a resource is unknown
+	Water decimal.NullDecimal
+	// Low and high estimations, when assumptions vary within their uncertainty
+	PowerRange           Range
+	CarbonEmissionsRange Range
+	// Number of resources estimated with specs that are default values or were not found
+	ResourcesWithDefaults int
+}
+
+// EstimatePlan returns the estimation report of a terraform plan in JSON, as output by `terraform show -json`
+func (e *Estimator) EstimatePlan(planJSON []byte) (PlanReport, error) {
+	var tfPlan map[string]interface{}
+	if err := json.Unmarshal(planJSON, &tfPlan); err != nil {
+		return PlanReport{}, errors.Wrap(err, "Invalid terraform plan JSON")
+	}
+	if _, ok := tfPlan["planned_values"]; !ok {
+		return PlanReport{}, errors.New("Invalid terraform plan JSON: no planned_values")
+	}
+
+	assumptions, err := plan.LoadAssumptions(e.config)
+	if err != nil {
+		return PlanReport{}, err
+	}
+	resources, err := plan.GetResourcesWithAssumptions(e.config, &tfPlan, assumptions)
+	if err != nil {
+		return PlanReport{}, errors.Wrap(err, "Failed to get resources from terraform plan")
+	}
+	for _, pattern := range assumptions.UnmatchedResources(resources) {
+		log.Warnf("Assumptions '%v' do not match any resource of the plan", pattern)
+	}
+	return toPlanReport(internalEstimate.EstimateResources(e.config, resources)), nil
+}
+
+// EstimatePlanFromReader returns the estimation report of a terraform plan in JSON read from r
+func (e *Estimator) EstimatePlanFromReader(r io.Reader) (PlanReport, error) {
+	planJSON, err := io.ReadAll(r)
+	if err != nil {
+		return PlanReport{}, errors.Wrap(err, "Cannot read terraform plan")
+	}
+	return e.EstimatePlan(planJSON)
+}
+
+func toPlanReport(report estimation.EstimationReport) PlanReport {
+	planReport := PlanReport{
+		Info: PlanInfo{
+			UnitTime:                report.Info.UnitTime,
+			UnitWattTime:            report.Info.UnitWattTime,
+			UnitCarbonEmissionsTime: report.Info.UnitCarbonEmissionsTime,
+			UnitWaterTime:           report.Info.UnitWaterTime,
+			DateTime:                report.Info.DateTime,
+		},
+		Resources:            []ResourceEstimation{},
+		UnsupportedResources: []ResourceIdentification{},
+		Total: PlanTotal{
+			Power:                  report.Total.Power,
+			CarbonEmissions:        report.Total.CarbonEmissions,
+			ResourcesCount:         report.Total.ResourcesCount,
+			NetworkPower:           report.Total.NetworkPower,
+			NetworkCarbonEmissions: report.Total.NetworkCarbonEmissions,
+			MarketBasedEmissions:   report.Total.MarketBasedEmissions,
+			EmbodiedEmissions:      report.Total.EmbodiedEmissions,
+			Water:                  report.Total.Water,
+			PowerRange:             Range(report.Total.PowerRange),
+			CarbonEmissionsRange:   Range(report.Total.CarbonEmissionsRange),
+			ResourcesWithDefaults:  report.Total.ResourcesWithDefaults,
+		},
+	}
+	for _, resource := range report.Resources {
+		planReport.Resources = append(planReport.Resources, ResourceEstimation{
+			Resource:               toResourceIdentification(resource.Resource),
+			Power:                  resource.Power,
+			CarbonEmissions:        resource.CarbonEmissions,
+			AverageCPUUsage:        resource.AverageCPUUsage,
+			AverageGPUUsage:        resource.AverageGPUUsage,
+			TotalCount:             resource.TotalCount,
+			CPUPlatform:            resource.CPUPlatform,
+			NetworkPower:           resource.NetworkPower,
+			NetworkCarbonEmissions: resource.NetworkCarbonEmissions,
+			MarketBasedEmissions:   resource.MarketBasedEmissions,
+			EmbodiedEmissions:      resource.EmbodiedEmissions,
+			Water:                  resource.Water,
+			PowerRange:             Range(resource.PowerRange),
+			CarbonEmissionsRange:   Range(resource.CarbonEmissionsRange),
+			DataQuality:            resource.DataQuality,
+			Warnings:               resource.Warnings,
+			Error:                  resource.Error,
+		})
+	}
+	for _, resource := range report.UnsupportedResources {
+		planReport.UnsupportedResources = append(planReport.UnsupportedResources, toResourceIdentification(resource))
+	}
+	return planReport
+}
+
+func toResourceIdentification(resource internalResources.Resource) ResourceIdentification {
+	identification := resource.GetIdentification()
+	return ResourceIdentification{
+		Address:           identification.Address,
+		Name:              identification.Name,
+		Type:              identification.ResourceType,
+		Provider:          providers.Provider(identification.Provider),
+		Region:            identification.Region,
+		Count:             identification.Count,
+		ReplicationFactor: identification.ReplicationFactor,
+		Spot:              identification.Spot,
+	}
+}
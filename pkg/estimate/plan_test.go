@@ -0,0 +1,100 @@
+package estimate
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/carboniferio/carbonifer/pkg/providers"
+	"github.com/stretchr/testify/assert"
+)
+
+const planJSON = `{
+  "format_version": "1.1",
+  "planned_values": {
+    "root_module": {
+      "resources": [
+        {
+          "address": "google_compute_instance.web",
+          "mode": "managed",
+          "type": "google_compute_instance",
+          "name": "web",
+          "provider_name": "registry.terraform.io/hashicorp/google",
+          "values": {
+            "name": "web",
+            "machine_type": "e2-standard-2",
+            "zone": "europe-west4-a",
+            "boot_disk": [],
+            "scratch_disk": [],
+            "guest_accelerator": []
+          }
+        },
+        {
+          "address": "google_compute_network.vpc",
+          "mode": "managed",
+          "type": "google_compute_network",
+          "name": "vpc",
+          "provider_name": "registry.terraform.io/hashicorp/google",
+          "values": {
+            "name": "vpc"
+          }
+        }
+      ]
+    }
+  }
+}`
+
+func TestEstimatePlan(t *testing.T) {
+	got, err := EstimatePlan([]byte(planJSON))
+	assert.NoError(t, err)
+	assert.Equal(t, "gCO2eq/h", got.Info.UnitCarbonEmissionsTime)
+
+	assert.Len(t, got.Resources, 1)
+	resource := got.Resources[0]
+	assert.Equal(t, ResourceIdentification{
+		Address:           "google_compute_instance.web",
+		Name:              "web",
+		Type:              "google_compute_instance",
+		Provider:          providers.GCP,
+		Region:            "europe-west4",
+		Count:             1,
+		ReplicationFactor: 1,
+	}, resource.Resource)
+	assert.Empty(t, resource.Error)
+	assert.True(t, resource.CarbonEmissions.IsPositive())
+	assert.Equal(t, resource.CarbonEmissions.String(), got.Total.CarbonEmissions.String())
+	assert.Equal(t, "1", got.Total.ResourcesCount.String())
+
+	assert.Len(t, got.UnsupportedResources, 1)
+	assert.Equal(t, "google_compute_network.vpc", got.UnsupportedResources[0].Address)
+
+	fromReader, err := EstimatePlanFromReader(strings.NewReader(planJSON))
+	assert.NoError(t, err)
+	assert.Equal(t, got.Resources, fromReader.Resources)
+	assert.Equal(t, got.Total, fromReader.Total)
+}
+
+func TestEstimatePlan_Partial(t *testing.T) {
+	f, err := os.Open("../../test/terraform/planJson/partial.json")
+	assert.NoError(t, err)
+	defer f.Close()
+
+	got, err := EstimatePlanFromReader(f)
+	assert.NoError(t, err)
+	assert.Len(t, got.Resources, 2)
+	for _, resource := range got.Resources {
+		if resource.Resource.Address == "google_compute_disk.broken" {
+			assert.Contains(t, resource.Error, "big")
+		} else {
+			assert.Empty(t, resource.Error)
+		}
+	}
+}
+
+func TestEstimatePlan_Invalid(t *testing.T) {
+	_, err := EstimatePlan([]byte("not json"))
+	assert.ErrorContains(t, err, "Invalid terraform plan JSON")
+
+	_, err = EstimatePlan([]byte(`{"format_version": "1.1"}`))
+	assert.ErrorContains(t, err, "no planned_values")
+}
@@ -2,11 +2,12 @@ package resources
 
 import (
 	"fmt"
+	"regexp"
 
 	internalProvider "github.com/carboniferio/carbonifer/internal/providers"
+	"github.com/carboniferio/carbonifer/internal/providers/aws"
 	"github.com/carboniferio/carbonifer/internal/providers/gcp"
 	"github.com/carboniferio/carbonifer/internal/resources"
-	"github.com/carboniferio/carbonifer/internal/utils"
 	"github.com/carboniferio/carbonifer/pkg/providers"
 	"github.com/shopspring/decimal"
 )
@@ -25,11 +26,11 @@ type GenericResource struct {
 	ReplicationFactor int32
 }
 
-// IsSupported returns true if the resource is supported by carbonifer. At the moment, only GCP is supported.
+// IsSupported returns true if the resource is supported by carbonifer. At the moment, GCP and AWS are supported.
 func (g GenericResource) IsSupported() bool {
 	// Use a switch to make it easier to add new providers
 	switch g.Provider {
-	case providers.GCP:
+	case providers.GCP, providers.AWS:
 		return true
 	default:
 		return false
@@ -60,11 +61,36 @@ type Storage struct {
 	SsdStorage decimal.Decimal
 }
 
-// GetResource returns a GenericResource from an instance type
+// zonePatterns extract the region of a zone, such as europe-west4 from europe-west4-a or eu-west-1 from eu-west-1a
+var zonePatterns = map[providers.Provider]*regexp.Regexp{
+	providers.GCP: regexp.MustCompile(`^(.+\d)-[a-z]$`),
+	providers.AWS: regexp.MustCompile(`^(.+-\d+)[a-z]$`),
+}
+
+// GetResource returns a GenericResource from an instance type, in a zone or a region, read from the embedded instance data files
 func GetResource(instanceType string, zone string, provider providers.Provider) (GenericResource, error) {
+	return GetResourceWithDataPath("", instanceType, zone, provider)
+}
+
+// GetResourceWithDataPath returns a GenericResource from an instance type, in a zone or a region, read from the
+// instance data files of a data directory, the embedded ones if empty
+func GetResourceWithDataPath(dataPath string, instanceType string, zone string, provider providers.Provider) (GenericResource, error) {
 	switch provider {
 	case providers.GCP:
-		return fromGCPMachineTypeToResource(zone, gcp.GetGCPMachineType(instanceType, zone)), nil
+		machineType, err := gcp.GetGCPMachineType(dataPath, instanceType, zone)
+		if err != nil {
+			return GenericResource{}, err
+		}
+		return fromGCPMachineTypeToResource(RegionOfZone(zone, provider), machineType), nil
+	case providers.AWS:
+		awsInstanceType, err := aws.GetAWSInstanceType(dataPath, instanceType)
+		if err != nil {
+			return GenericResource{}, err
+		}
+		return fromAWSInstanceTypeToResource(RegionOfZone(zone, provider), awsInstanceType), nil
+	case providers.AZURE:
+		// No data of Azure VM sizes yet
+		return GenericResource{}, fmt.Errorf("provider %s not supported yet, no data of its VM sizes", provider.String())
 	default:
 		return GenericResource{}, fmt.Errorf("provider %s not supported", provider.String())
 	}
@@ -84,6 +110,31 @@ func fromGCPMachineTypeToResource(region string, machineType gcp.MachineType) Ge
 	}
 }
 
-func init() {
-	utils.InitWithDefaultConfig()
+func fromAWSInstanceTypeToResource(region string, instanceType aws.InstanceType) GenericResource {
+	instanceStorage := decimal.NewFromInt(instanceType.InstanceStorage.SizePerDiskGB).Mul(decimal.NewFromInt32(instanceType.InstanceStorage.Count))
+	storage := Storage{}
+	switch instanceType.InstanceStorage.Type {
+	case "ssd":
+		storage.SsdStorage = instanceStorage
+	case "hdd":
+		storage.HddStorage = instanceStorage
+	}
+	return GenericResource{
+		Name:              instanceType.InstanceType,
+		Region:            region,
+		Provider:          providers.AWS,
+		GPUTypes:          instanceType.GPUTypes(),
+		MemoryMb:          instanceType.MemoryMb,
+		VCPUs:             instanceType.VCPU,
+		Storage:           storage,
+		ReplicationFactor: 0,
+	}
+}
+
+// RegionOfZone returns the region of a zone, or the zone itself if it is already a region
+func RegionOfZone(zone string, provider providers.Provider) string {
+	if match := zonePatterns[provider].FindStringSubmatch(zone); match != nil {
+		return match[1]
+	}
+	return zone
 }
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/carboniferio/carbonifer/pkg/providers"
+	"github.com/shopspring/decimal"
 )
 
 func TestGenericResource_IsSupported(t *testing.T) {
@@ -35,6 +36,13 @@ func TestGenericResource_IsSupported(t *testing.T) {
 			fields: fields{
 				Provider: providers.AWS,
 			},
+			want: true,
+		},
+		{
+			name: "AZURE",
+			fields: fields{
+				Provider: providers.AZURE,
+			},
 			want: false,
 		},
 	}
@@ -78,7 +86,7 @@ func TestGetResource(t *testing.T) {
 			},
 			want: GenericResource{
 				Name:     "e2-standard-2",
-				Region:   "europe-west4-a",
+				Region:   "europe-west4",
 				Provider: providers.GCP,
 				CPUTypes: []string{
 					"Skylake",
@@ -94,6 +102,63 @@ func TestGetResource(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "m6i.large",
+			args: args{
+				instanceType: "m6i.large",
+				zone:         "eu-west-1a",
+				provider:     providers.AWS,
+			},
+			want: GenericResource{
+				Name:     "m6i.large",
+				Region:   "eu-west-1",
+				Provider: providers.AWS,
+				VCPUs:    2,
+				MemoryMb: 8192,
+				Storage:  Storage{},
+			},
+			wantErr: false,
+		},
+		{
+			name: "g4dn.12xlarge",
+			args: args{
+				instanceType: "g4dn.12xlarge",
+				zone:         "us-east-1",
+				provider:     providers.AWS,
+			},
+			want: GenericResource{
+				Name:     "g4dn.12xlarge",
+				Region:   "us-east-1",
+				Provider: providers.AWS,
+				GPUTypes: []string{"nvidia-tesla-t4", "nvidia-tesla-t4", "nvidia-tesla-t4", "nvidia-tesla-t4"},
+				VCPUs:    48,
+				MemoryMb: 196608,
+				Storage: Storage{
+					SsdStorage: decimal.NewFromInt(900),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "unknown AWS instance type",
+			args: args{
+				instanceType: "x9.unknown",
+				zone:         "eu-west-1a",
+				provider:     providers.AWS,
+			},
+			want:    GenericResource{},
+			wantErr: true,
+		},
+		{
+			name: "Azure",
+			args: args{
+				instanceType: "Standard_D2s_v3",
+				zone:         "westeurope",
+				provider:     providers.AZURE,
+			},
+			want:    GenericResource{},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {